@@ -16,6 +16,8 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
 
 	"ariga.io/atlas/sql/schema"
@@ -49,6 +51,7 @@ var (
 
 	loadExistSchemaFile bool // 기존 스키마 파일에서 로딩, 스키마 파일대로 db migrate
 	loadExistConfigFile bool // 기존 설정 파일에서 로딩
+	diagnosticsJSON     bool // 쿼리 에러를 json 으로 출력 (에디터 연동)
 	configFilePath      string
 )
 
@@ -57,6 +60,7 @@ func init() {
 	fs.StringVarP(&configFilePath, "config", "c", "config.toml", "Path to config file")
 	fs.BoolVar(&loadExistSchemaFile, "load_schema", true, "load schema from existing file and migrate database")
 	fs.BoolVar(&loadExistConfigFile, "load_config", false, "load config from existing file")
+	fs.BoolVar(&diagnosticsJSON, "diagnostics_json", false, "print query errors as json to stdout")
 }
 
 func main() {
@@ -144,13 +148,31 @@ func rootRun(cmd *cobra.Command, args []string) {
 	}
 
 	// 5. gen code
+	var diags gen.Diagnostics
 	var gen *gen.ORNN = &gen.ORNN{}
 	{
 		gen.Init(conf, psr)
 		if err = gen.GenCode(); err != nil { // code generate
+			if errors.As(err, &diags) {
+				printDiagnostics(diags)
+				log.Panic().Int("count", len(diags)).Msg("query error")
+			}
 			log.Panic().Err(err).Msg("code generate error")
 		}
 	}
 	log.Info().Str("generate path", cfg.Gen.GenPath).Msg("Code generated Succeed")
 
 }
+
+// printDiagnostics 는 쿼리 에러를 컴파일러 형식으로 stderr 에, diagnostics_json 이면 json 으로 stdout 에 출력
+func printDiagnostics(diags gen.Diagnostics) {
+	if diagnosticsJSON {
+		data, err := diags.JSON()
+		if err != nil {
+			log.Panic().Err(err).Msg("diagnostics json error")
+		}
+		fmt.Println(string(data))
+		return
+	}
+	fmt.Fprint(os.Stderr, diags.Error())
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"path"
+	"strings"
 
 	"ariga.io/atlas/sql/schema"
 	"github.com/gosuda/ornn/atlas"
@@ -13,6 +16,8 @@ type Config struct {
 	Queries Queries `json:"queries"`
 
 	Schema Schema `json:"-"`
+
+	Path string `json:"-"` // file the config was loaded from
 }
 
 // TODO - 추후 config 형식 변경 예정
@@ -26,6 +31,7 @@ func (t *Config) Load(path string) error {
 	if err != nil {
 		return err
 	}
+	t.Path = path
 
 	return nil
 }
@@ -45,6 +51,31 @@ func (t *Config) Init(dbType atlas.DbType, schema *schema.Schema, filePath, file
 
 	// init schema
 	t.Schema.Init(dbType, schema)
+	t.Schema.Strict = t.Global.Strict
+	for _, override := range t.Global.TypeOverrides {
+		if err := override.validate(); err != nil {
+			return err
+		}
+	}
+	t.Schema.TypeOverrides = t.Global.TypeOverrides
+	switch t.Global.Nullable {
+	case "", NullablePointer, NullableSQL, NullableGeneric:
+		t.Schema.Nullable = t.Global.Nullable
+	default:
+		return fmt.Errorf("nullable %s is not one of %s, %s and %s", t.Global.Nullable, NullablePointer, NullableSQL, NullableGeneric)
+	}
+	switch t.Global.SQLiteTimeFormat {
+	case "", TimeFormatText, TimeFormatUnix, TimeFormatJulian:
+		t.Schema.TimeFormat = t.Global.SQLiteTimeFormat
+	default:
+		return fmt.Errorf("sqlite_time_format %s is not one of %s, %s and %s", t.Global.SQLiteTimeFormat, TimeFormatText, TimeFormatUnix, TimeFormatJulian)
+	}
+	switch t.Global.Decimal {
+	case "", DecimalFloat, DecimalString:
+		t.Schema.Decimal = t.Global.Decimal
+	default:
+		return fmt.Errorf("decimal %s is not one of %s and %s", t.Global.Decimal, DecimalFloat, DecimalString)
+	}
 
 	// init queries by schema
 	t.Queries.init(&t.Schema)
@@ -62,6 +93,7 @@ func (t *Config) Save(path string) error {
 	if err != nil {
 		return err
 	}
+	t.Path = path
 	return nil
 }
 
@@ -73,9 +105,80 @@ type Global struct {
 	ClassName   string `json:"class_name"`
 
 	Import []*Import `json:"import"`
+
+	// postgres only, generates a CopyFrom<Table> loader per table using COPY FROM
+	CopyFrom bool `json:"copy_from,omitempty"`
+
+	// columns and tables missing from the schema are errors instead of "any" typed
+	Strict bool `json:"strict,omitempty"`
+
+	// go types of the columns matching db type, table.column or column name pattern, for every query
+	TypeOverrides []*TypeOverride `json:"type_overrides,omitempty"`
+
+	// representation of nullable columns: pointer (*string, default), sql (sql.NullString) or generic (Null[string])
+	Nullable string `json:"nullable,omitempty"`
+
+	// sqlite only, storage format of date and time columns: text (ISO8601, default), unix (epoch integer) or julian (real)
+	SQLiteTimeFormat string `json:"sqlite_time_format,omitempty"`
+
+	// mysql and postgres, representation of decimal and numeric columns: float (float64, default) or string (exact).
+	// a decimal library type is set with a type override of the db type, e.g. numeric → decimal.Decimal
+	Decimal string `json:"decimal,omitempty"`
 }
 
+// nullable representations of nullable columns
+const (
+	NullablePointer = "pointer" // *string
+	NullableSQL     = "sql"     // sql.NullString, sql.Null[T] for types without a sql.Null* type
+	NullableGeneric = "generic" // Null[string] of the db package
+)
+
+// storage formats of times in sqlite, which has no time type
+const (
+	TimeFormatText   = "text"   // ISO8601 text, TextTime
+	TimeFormatUnix   = "unix"   // unix epoch seconds integer, UnixTime
+	TimeFormatJulian = "julian" // julian day number real, JulianTime
+)
+
+// representations of exact numerics, decimal and numeric columns
+const (
+	DecimalFloat  = "float"  // float64, default
+	DecimalString = "string" // string, int64 for integers of up to 18 digits
+)
+
 type Import struct {
 	Alias string `json:"alias"`
 	Path  string `json:"path"`
 }
+
+// TypeOverride maps the columns matching one of DbType, Column or ColumnPattern to GoType.
+// A table.column override wins over a column pattern, and a column pattern over a db type.
+type TypeOverride struct {
+	DbType        string `json:"db_type,omitempty"`        // column type without size, e.g. uuid, jsonb
+	Column        string `json:"column,omitempty"`         // table.column
+	ColumnPattern string `json:"column_pattern,omitempty"` // column name glob, e.g. *_uuid
+
+	GoType string `json:"go_type"`          // nullable columns are wrapped in the nullable representation
+	Import string `json:"import,omitempty"` // import path of GoType, added to the generated code
+}
+
+func (t *TypeOverride) validate() error {
+	keys := 0
+	for _, key := range []string{t.DbType, t.Column, t.ColumnPattern} {
+		if key != "" {
+			keys++
+		}
+	}
+	switch {
+	case t.GoType == "":
+		return fmt.Errorf("type override needs go_type")
+	case keys != 1:
+		return fmt.Errorf("type override %s needs exactly one of db_type, column and column_pattern", t.GoType)
+	case t.Column != "" && strings.Count(t.Column, ".") != 1:
+		return fmt.Errorf("type override %s | column %s is not table.column", t.GoType, t.Column)
+	}
+	if _, err := path.Match(t.ColumnPattern, ""); err != nil {
+		return fmt.Errorf("type override %s | column_pattern %s | %v", t.GoType, t.ColumnPattern, err)
+	}
+	return nil
+}
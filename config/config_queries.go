@@ -132,12 +132,19 @@ type Query struct {
 	Sql     string `json:"sql"`
 
 	// options
-	CustomFieldTypes []*CustomFieldType `json:"custom_field_types,omitempty"`
-	UpdateNullIgnore bool               `json:"update_null_ignore,omitempty"`
-	ErrQuery         string             `json:"-"`
-	ErrParser        string             `json:"-"`
+	CustomFieldTypes []*CustomFieldType  `json:"custom_field_types,omitempty"`
+	UpdateNullIgnore bool                `json:"update_null_ignore,omitempty"` // SET args are pointers, the assignments of nil args are skipped
+	Bulk             bool                `json:"bulk,omitempty"`               // single-row insert executed for a slice of rows as multi-row inserts
+	Optional         []string            `json:"optional,omitempty"`           // args (by name or column) whose WHERE predicate is dropped when nil
+	TplValues        map[string][]string `json:"tpl_values,omitempty"`         // allowed values of each #tpl#, any identifier if not set
+	OrderBy          []string            `json:"order_by,omitempty"`           // sortable columns (column or table.column), the ORDER BY is chosen at runtime
+	Paginate         bool                `json:"paginate,omitempty"`           // select also generates a keyset (cursor) paginated Page func
+	ErrQuery         string              `json:"-"`
+	ErrParser        string              `json:"-"`
 }
 
+// CustomFieldType sets the go type of the args and rets bound to a column, json columns are marshaled from and to it.
+// An empty table name matches the column of any table.
 type CustomFieldType struct {
 	TableName  string `json:"table_name"`
 	FieldName  string `json:"field_name"`
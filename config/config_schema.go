@@ -1,12 +1,21 @@
 package config
 
 import (
+	"path"
+	"strings"
+
 	"ariga.io/atlas/sql/schema"
 	"github.com/gosuda/ornn/atlas"
 )
 
 type Schema struct {
 	DbType atlas.DbType `json:"-"`
+	Strict bool         `json:"-"` // unknown columns are parser errors instead of "any" typed
+
+	TypeOverrides []*TypeOverride `json:"-"`
+	Nullable      string          `json:"-"` // representation of nullable columns, Nullable*
+	TimeFormat    string          `json:"-"` // sqlite storage format of times, TimeFormat*
+	Decimal       string          `json:"-"` // representation of decimal and numeric columns, Decimal*
 
 	*schema.Schema `json:"-"`
 }
@@ -16,6 +25,50 @@ func (t *Schema) Init(dbType atlas.DbType, sch *schema.Schema) {
 	t.Schema = sch
 }
 
+// OverrideType returns the go type of the type override matching the column.
+func (t *Schema) OverrideType(colType *schema.ColumnType) (goType string, ok bool) {
+	if len(t.TypeOverrides) == 0 || colType == nil {
+		return "", false
+	}
+
+	var tableName, columnName string
+	if t.Schema != nil {
+		for _, tbl := range t.Tables {
+			for _, col := range tbl.Columns {
+				if col.Type == colType {
+					tableName, columnName = tbl.Name, col.Name
+				}
+			}
+		}
+	}
+	dbType := strings.ToLower(colType.Raw)
+	if i := strings.IndexByte(dbType, '('); i != -1 {
+		dbType = strings.TrimSpace(dbType[:i])
+	}
+
+	var match *TypeOverride
+	rank := 0
+	for _, override := range t.TypeOverrides {
+		switch {
+		case override.Column != "" && columnName != "" && override.Column == tableName+"."+columnName:
+			match, rank = override, 3
+		case rank < 2 && override.ColumnPattern != "" && columnName != "":
+			if ok, _ := path.Match(override.ColumnPattern, columnName); ok {
+				match, rank = override, 2
+			}
+		case rank < 1 && override.DbType != "" && strings.EqualFold(override.DbType, dbType):
+			match, rank = override, 1
+		}
+		if rank == 3 {
+			break
+		}
+	}
+	if match == nil {
+		return "", false
+	}
+	return match.GoType, true
+}
+
 func (t *Schema) GetFieldTypeAll(fieldName string) (fieldTypeByTable map[string]string, exist bool) {
 	fieldTypeByTable = make(map[string]string)
 	for _, tbl := range t.Tables {
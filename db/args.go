@@ -0,0 +1,17 @@
+package db
+
+// I_to_arri flattens the args of every row into a single arg list, in row order.
+// rowArgs returns the args of a row, e.g. the fields bound to the VALUES row of a bulk insert.
+func I_to_arri[T any](rows []T, rowArgs func(row *T) []any) []any {
+	if len(rows) == 0 {
+		return []any{}
+	}
+
+	first := rowArgs(&rows[0])
+	args := make([]any, 0, len(rows)*len(first))
+	args = append(args, first...)
+	for i := 1; i < len(rows); i++ {
+		args = append(args, rowArgs(&rows[i])...)
+	}
+	return args
+}
@@ -0,0 +1,22 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestI_to_arri(t *testing.T) {
+	type row struct {
+		Name string
+		Age  int32
+	}
+	rowArgs := func(r *row) []any { return []any{r.Name, r.Age} }
+
+	args := I_to_arri([]row{{"a", 1}, {"b", 2}}, rowArgs)
+	require.Equal(t, []any{"a", int32(1), "b", int32(2)}, args)
+
+	args = I_to_arri([]row{}, rowArgs)
+	require.NotNil(t, args)
+	require.Empty(t, args)
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// bind arg limits of a single statement per driver, bulk inserts are chunked to stay under them
+const (
+	BulkMaxArgsMySQL    = 65535
+	BulkMaxArgsPostgres = 65535
+	BulkMaxArgsSQLite   = 32766
+)
+
+// BulkValues repeats the VALUES row of a single-row insert rows times.
+// Numbered placeholders ($n) of each row are shifted by the args of the rows before it.
+// Every placeholder of the query must be in the VALUES row.
+func BulkValues(query string, rows, argsPerRow int) (string, error) {
+	start, end := valuesRow(query)
+	if start == -1 {
+		return "", fmt.Errorf("bulk insert needs a single VALUES (...) row | %s", query)
+	}
+	numbered := hasNumberedArg(query)
+	if n := CountArgs(query[:start], numbered) + CountArgs(query[end:], numbered); n > 0 {
+		return "", fmt.Errorf("bulk insert has %d placeholders outside the VALUES row | %s", n, query)
+	}
+
+	row := query[start:end]
+	var out strings.Builder
+	out.Grow(len(query) + (rows-1)*(len(row)+2))
+	out.WriteString(query[:end])
+	for r := 1; r < rows; r++ {
+		out.WriteString(", ")
+		if numbered == false {
+			out.WriteString(row)
+			continue
+		}
+		for i := 0; i < len(row); {
+			if end := SkipQuoted(row, i); end > i {
+				out.WriteString(row[i:end])
+				i = end
+				continue
+			}
+			n, next := numberedArg(row, i)
+			if n == 0 {
+				out.WriteByte(row[i])
+				i++
+				continue
+			}
+			out.WriteString("$" + strconv.Itoa(n+r*argsPerRow))
+			i = next
+		}
+	}
+	out.WriteString(query[end:])
+	return out.String(), nil
+}
+
+// BulkInsert executes a single-row insert for every row of args (argsPerRow args each) as
+// multi-row inserts of at most maxArgs bind args, and returns the total affected rows.
+// Chunks are executed one by one, run it in a transaction to insert all or nothing.
+func (t *Job) BulkInsert(query string, argsPerRow, maxArgs int, args ...any) (rowAffected int64, err error) {
+	if argsPerRow <= 0 || len(args)%argsPerRow != 0 {
+		return 0, fmt.Errorf("bulk insert args (%d) are not a multiple of args per row (%d)", len(args), argsPerRow)
+	}
+	chunkRows := maxArgs / argsPerRow
+	if chunkRows < 1 {
+		chunkRows = 1
+	}
+
+	for rows := len(args) / argsPerRow; rows > 0; {
+		n := min(rows, chunkRows)
+		chunkQuery, err := BulkValues(query, n, argsPerRow)
+		if err != nil {
+			return rowAffected, err
+		}
+		exec, err := t.Exec(chunkQuery, args[:n*argsPerRow]...)
+		if err != nil {
+			return rowAffected, err
+		}
+		affected, err := exec.RowsAffected()
+		if err != nil {
+			return rowAffected, err
+		}
+		rowAffected += affected
+		args, rows = args[n*argsPerRow:], rows-n
+	}
+	return rowAffected, nil
+}
+
+// valuesRow returns where the parenthesized row after VALUES starts and ends, or -1.
+func valuesRow(query string) (start, end int) {
+	start, depth := -1, 0
+	for i := 0; i < len(query); i++ {
+		if end := SkipQuoted(query, i); end > i {
+			i = end - 1
+			continue
+		}
+		switch {
+		case start == -1 && depth == 0 && isKeywordAt(query, i, "VALUES"):
+			start = i + len("VALUES")
+			for start < len(query) && strings.IndexByte(" \t\r\n", query[start]) != -1 {
+				start++
+			}
+			if start == len(query) || query[start] != '(' {
+				return -1, -1
+			}
+			i = start - 1
+		case query[i] == '(':
+			depth++
+		case query[i] == ')':
+			depth--
+			if start != -1 && depth == 0 {
+				return start, i + 1
+			}
+		}
+	}
+	return -1, -1
+}
+
+// CountArgs counts the args bound to the placeholders of a query, one per ? or per distinct $n if numbered,
+// outside quotes and comments.
+func CountArgs(query string, numbered bool) (count int) {
+	seen := make(map[int]bool)
+	for i := 0; i < len(query); i++ {
+		if end := SkipQuoted(query, i); end > i {
+			i = end - 1
+			continue
+		}
+		if n, _ := numberedArg(query, i); numbered == true && n > 0 && seen[n] == false {
+			seen[n] = true
+			count++
+		} else if numbered == false && query[i] == '?' {
+			count++
+		}
+	}
+	return count
+}
+
+// isKeywordAt reports whether the keyword starts at i as a whole word.
+func isKeywordAt(query string, i int, keyword string) bool {
+	end := i + len(keyword)
+	if end > len(query) || strings.EqualFold(query[i:end], keyword) == false {
+		return false
+	}
+	return (i == 0 || IsIdentChar(query[i-1]) == false) && (end == len(query) || IsIdentChar(query[end]) == false)
+}
+
+// numberedArg returns n of the $n placeholder at i and where it ends, or 0.
+func numberedArg(query string, i int) (n, end int) {
+	if query[i] != '$' {
+		return 0, i
+	}
+	end = i + 1
+	for end < len(query) && query[end] >= '0' && query[end] <= '9' {
+		end++
+	}
+	n, _ = strconv.Atoi(query[i+1 : end])
+	return n, end
+}
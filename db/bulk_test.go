@@ -0,0 +1,49 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkValues(t *testing.T) {
+	query, err := BulkValues("INSERT INTO t (a, b) VALUES (?, ?)", 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO t (a, b) VALUES (?, ?), (?, ?), (?, ?)", query)
+
+	query, err = BulkValues("INSERT INTO t (a, b, c) VALUES ($1, lower($2), '$1') ON CONFLICT (a) DO NOTHING", 3, 2)
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO t (a, b, c) VALUES ($1, lower($2), '$1'), ($3, lower($4), '$1'), ($5, lower($6), '$1') ON CONFLICT (a) DO NOTHING", query)
+
+	_, err = BulkValues("INSERT INTO t (a) VALUES ($1) ON CONFLICT (a) DO UPDATE SET a = $2", 2, 1)
+	require.Error(t, err)
+
+	_, err = BulkValues("INSERT INTO t SELECT * FROM u", 2, 1)
+	require.Error(t, err)
+}
+
+func TestCountArgs(t *testing.T) {
+	require.Equal(t, 3, CountArgs("SELECT * FROM t WHERE a = ? AND b = '?' AND c IN (?, ?) -- ?", false))
+	require.Equal(t, 2, CountArgs("SELECT * FROM t WHERE a = $1 AND b = $2 OR a = $1 AND c = '$3'", true))
+}
+
+func TestBulkInsertChunk(t *testing.T) {
+	job := newRecordJob(t)
+	rowAffected, err := job.BulkInsert("INSERT INTO t (a, b) VALUES ($1, $2)", 2, 4, 1, "a", 2, "b", 3, "c")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), rowAffected)
+	require.Equal(t, []string{
+		"INSERT INTO t (a, b) VALUES ($1, $2), ($3, $4)",
+		"INSERT INTO t (a, b) VALUES ($1, $2)",
+	}, recorded)
+
+	// nothing to insert
+	recorded = nil
+	rowAffected, err = job.BulkInsert("INSERT INTO t (a, b) VALUES ($1, $2)", 2, 4)
+	require.NoError(t, err)
+	require.Zero(t, rowAffected)
+	require.Empty(t, recorded)
+
+	_, err = job.BulkInsert("INSERT INTO t (a, b) VALUES ($1, $2)", 2, 4, 1)
+	require.Error(t, err)
+}
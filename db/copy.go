@@ -0,0 +1,48 @@
+package db
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// CopyFrom loads rows into the table with the postgres COPY protocol (lib/pq), rowArgs returns the
+// column values of the i-th row. COPY runs in the job transaction, or in its own one when the job is not a transaction.
+func (t *Job) CopyFrom(table string, columns []string, rows int, rowArgs func(i int) []any) (rowAffected int64, err error) {
+	tx := t.tx
+	if tx == nil {
+		tx, err = t.db.Begin()
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+				return
+			}
+			err = tx.Commit()
+		}()
+	}
+	return copyIn(tx, table, columns, rows, rowArgs)
+}
+
+func copyIn(tx *sql.Tx, table string, columns []string, rows int, rowArgs func(i int) []any) (rowAffected int64, err error) {
+	stmt, err := tx.Prepare(pq.CopyIn(table, columns...))
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+
+	for i := 0; i < rows; i++ {
+		if _, err := stmt.Exec(rowArgs(i)...); err != nil {
+			return 0, err
+		}
+	}
+
+	// exec without args flushes the buffered rows
+	res, err := stmt.Exec()
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyFrom(t *testing.T) {
+	job := newRecordJob(t)
+
+	names := []string{"a", "b", "c"}
+	rowAffected, err := job.CopyFrom("users", []string{"id", "name"}, len(names), func(i int) []any {
+		return []any{int64(i), names[i]}
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), rowAffected)
+	require.Equal(t, []string{`COPY "users" ("id", "name") FROM STDIN`, "COMMIT"}, recorded)
+
+	// in a transaction job, the caller commits
+	recorded = nil
+	require.NoError(t, job.BeginTx(0, false))
+	_, err = job.CopyFrom("users", []string{"id"}, 1, func(i int) []any { return []any{int64(i)} })
+	require.NoError(t, err)
+	require.Equal(t, []string{`COPY "users" ("id") FROM STDIN`}, recorded)
+	require.NoError(t, job.Commit())
+}
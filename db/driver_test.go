@@ -0,0 +1,71 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordDriver records executed statements instead of running them.
+// An Exec affects one row per 2 args, a prepared statement counts its rows until it is executed without args (COPY flush).
+var (
+	recorded     []string
+	registerOnce sync.Once
+)
+
+func newRecordJob(t *testing.T) *Job {
+	t.Helper()
+	registerOnce.Do(func() {
+		sql.Register("record", &recordDriver{})
+	})
+	conn, err := sql.Open("record", "")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	recorded = nil
+	return NewJob(conn)
+}
+
+type recordDriver struct{}
+
+func (d *recordDriver) Open(name string) (driver.Conn, error) { return &recordConn{}, nil }
+
+type recordConn struct{}
+
+func (c *recordConn) Prepare(query string) (driver.Stmt, error) {
+	return &recordStmt{query: query}, nil
+}
+func (c *recordConn) Close() error              { return nil }
+func (c *recordConn) Begin() (driver.Tx, error) { return &recordTx{}, nil }
+
+func (c *recordConn) Exec(query string, args []driver.Value) (driver.Result, error) {
+	recorded = append(recorded, query)
+	return driver.RowsAffected(len(args) / 2), nil
+}
+
+type recordTx struct{}
+
+func (tx *recordTx) Commit() error   { recorded = append(recorded, "COMMIT"); return nil }
+func (tx *recordTx) Rollback() error { recorded = append(recorded, "ROLLBACK"); return nil }
+
+type recordStmt struct {
+	query string
+	rows  int64
+}
+
+func (s *recordStmt) Close() error  { return nil }
+func (s *recordStmt) NumInput() int { return -1 }
+
+func (s *recordStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if len(args) > 0 {
+		s.rows++
+		return driver.RowsAffected(0), nil
+	}
+	recorded = append(recorded, s.query)
+	return driver.RowsAffected(s.rows), nil
+}
+
+func (s *recordStmt) Query(args []driver.Value) (driver.Rows, error) { return nil, driver.ErrSkip }
@@ -0,0 +1,38 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Enum is a generated enum type, a string type with a constant per value of the database enum.
+type Enum interface {
+	~string
+	Valid() bool
+}
+
+// ScanEnum scans a string column into an enum, values outside the enum are errors.
+func ScanEnum[T Enum](dst *T, src any) error {
+	var val T
+	switch src := src.(type) {
+	case string:
+		val = T(src)
+	case []byte:
+		val = T(src)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, val)
+	}
+	if val.Valid() == false {
+		return fmt.Errorf("invalid %T value %q", val, string(val))
+	}
+	*dst = val
+	return nil
+}
+
+// EnumValue returns the driver value of an enum, values outside the enum are errors.
+func EnumValue[T Enum](val T) (driver.Value, error) {
+	if val.Valid() == false {
+		return nil, fmt.Errorf("invalid %T value %q", val, string(val))
+	}
+	return string(val), nil
+}
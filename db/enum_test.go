@@ -0,0 +1,43 @@
+package db
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testRole string
+
+func (t testRole) Valid() bool {
+	return t == "owner" || t == "member"
+}
+
+func (t *testRole) Scan(src any) error {
+	return ScanEnum(t, src)
+}
+
+func (t testRole) Value() (driver.Value, error) {
+	return EnumValue(t)
+}
+
+func TestScanEnum(t *testing.T) {
+	var role testRole
+	require.NoError(t, role.Scan([]byte("owner")))
+	require.Equal(t, testRole("owner"), role)
+	require.NoError(t, role.Scan("member"))
+	require.Equal(t, testRole("member"), role)
+
+	require.EqualError(t, role.Scan("admin"), `invalid db.testRole value "admin"`)
+	require.Equal(t, testRole("member"), role)
+	require.EqualError(t, role.Scan(int64(1)), "cannot scan int64 into db.testRole")
+}
+
+func TestEnumValue(t *testing.T) {
+	val, err := testRole("owner").Value()
+	require.NoError(t, err)
+	require.Equal(t, "owner", val)
+
+	_, err = testRole("").Value()
+	require.EqualError(t, err, `invalid db.testRole value ""`)
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval is a postgres interval as a time.Duration.
+// Months and years have no fixed length, they are read as 30 and 365.25 days like EXTRACT(EPOCH FROM interval).
+type Interval struct{ time.Duration }
+
+const (
+	intervalDay   = 24 * time.Hour
+	intervalMonth = 30 * intervalDay
+	intervalYear  = 365*intervalDay + 6*time.Hour
+)
+
+// Scan parses the interval text of the postgres style, e.g. 1 year 2 mons 3 days 04:05:06.789.
+func (t *Interval) Scan(src any) error {
+	var text string
+	switch src := src.(type) {
+	case []byte:
+		text = string(src)
+	case string:
+		text = src
+	case int64:
+		// microseconds
+		t.Duration = time.Duration(src) * time.Microsecond
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, t)
+	}
+
+	var d time.Duration
+	fields := strings.Fields(text)
+	for i := 0; i < len(fields); i++ {
+		if strings.Contains(fields[i], ":") {
+			clock, err := parseIntervalClock(fields[i])
+			if err != nil {
+				return fmt.Errorf("invalid interval %q", text)
+			}
+			d += clock
+			continue
+		}
+		if i+1 >= len(fields) {
+			return fmt.Errorf("invalid interval %q", text)
+		}
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid interval %q", text)
+		}
+		i++
+		switch unit := strings.TrimSuffix(fields[i], "s"); unit {
+		case "year":
+			d += time.Duration(n) * intervalYear
+		case "mon":
+			d += time.Duration(n) * intervalMonth
+		case "day":
+			d += time.Duration(n) * intervalDay
+		default:
+			return fmt.Errorf("invalid interval %q", text)
+		}
+	}
+	t.Duration = d
+	return nil
+}
+
+// Value formats the interval as hours:minutes:seconds, which postgres reads for any length.
+func (t Interval) Value() (driver.Value, error) {
+	d := t.Duration
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	text := fmt.Sprintf("%s%02d:%02d:%02d", sign, d/time.Hour, d%time.Hour/time.Minute, d%time.Minute/time.Second)
+	if micros := d % time.Second / time.Microsecond; micros > 0 {
+		text += strings.TrimRight(fmt.Sprintf(".%06d", micros), "0")
+	}
+	return text, nil
+}
+
+// parseIntervalClock parses [-]hh:mm[:ss[.ffffff]]
+func parseIntervalClock(s string) (time.Duration, error) {
+	negative := strings.HasPrefix(s, "-")
+	parts := strings.Split(strings.TrimLeft(s, "+-"), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid interval time %q", s)
+	}
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	if len(parts) == 3 {
+		seconds, err := time.ParseDuration(parts[2] + "s")
+		if err != nil {
+			return 0, err
+		}
+		d += seconds
+	}
+	if negative {
+		d = -d
+	}
+	return d, nil
+}
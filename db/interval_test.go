@@ -0,0 +1,43 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntervalScanValue(t *testing.T) {
+	for text, want := range map[string]time.Duration{
+		"00:00:00":                0,
+		"04:05:06.789":            4*time.Hour + 5*time.Minute + 6789*time.Millisecond,
+		"-00:00:01":               -time.Second,
+		"3 days":                  72 * time.Hour,
+		"1 day 02:00:00":          26 * time.Hour,
+		"-1 days +01:00:00":       -23 * time.Hour,
+		"1 mon":                   30 * 24 * time.Hour,
+		"1 year 2 mons":           (365*24+6)*time.Hour + 60*24*time.Hour,
+		"100:00:00":               100 * time.Hour,
+		"2 years 00:00:00.000001": 2*(365*24+6)*time.Hour + time.Microsecond,
+	} {
+		var interval Interval
+		require.NoError(t, interval.Scan([]byte(text)), text)
+		require.Equal(t, want, interval.Duration, text)
+
+		// round trip
+		val, err := interval.Value()
+		require.NoError(t, err)
+		var again Interval
+		require.NoError(t, again.Scan(val))
+		require.Equal(t, interval, again, text)
+	}
+
+	val, err := Interval{26*time.Hour + 1500*time.Millisecond}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "26:00:01.5", val)
+
+	var interval Interval
+	require.Error(t, interval.Scan("3 weeks"))
+	require.Error(t, interval.Scan("1"))
+	require.Error(t, interval.Scan(1.5))
+}
@@ -34,6 +34,13 @@ func (t *Job) Query(query string, args ...any) (rows *sql.Rows, err error) {
 	return rows, err
 }
 
+func (t *Job) QueryRow(query string, args ...any) *sql.Row {
+	if t.tx == nil {
+		return t.db.QueryRow(query, args...)
+	}
+	return t.tx.QueryRow(query, args...)
+}
+
 func (t *Job) BeginTx(isoLevel sql.IsolationLevel, readonly bool) error {
 	var err error
 	t.tx, err = t.db.BeginTx(context.Background(), &sql.TxOptions{
@@ -59,3 +66,28 @@ func (t *Job) Rollback() error {
 	}
 	return t.tx.Rollback()
 }
+
+// Transaction runs fn with a transaction job, committed when fn returns nil and rolled back otherwise.
+// On a transaction job fn joins the running transaction, which is left to the caller.
+func (t *Job) Transaction(fn func(job *Job) error) (err error) {
+	if t.tx != nil {
+		return fn(t)
+	}
+
+	job := &Job{db: t.db}
+	if err = job.BeginTx(sql.LevelDefault, false); err != nil {
+		return err
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			job.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			job.Rollback()
+			return
+		}
+		err = job.Commit()
+	}()
+	return fn(job)
+}
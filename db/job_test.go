@@ -0,0 +1,36 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction(t *testing.T) {
+	job := newRecordJob(t)
+
+	err := job.Transaction(func(tx *Job) error {
+		_, err := tx.Exec("UPDATE users SET age = 1")
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"UPDATE users SET age = 1", "COMMIT"}, recorded)
+
+	recorded = nil
+	err = job.Transaction(func(tx *Job) error {
+		return errors.New("fail")
+	})
+	require.EqualError(t, err, "fail")
+	require.Equal(t, []string{"ROLLBACK"}, recorded)
+
+	// joins the running transaction
+	recorded = nil
+	require.NoError(t, job.BeginTx(0, false))
+	require.NoError(t, job.Transaction(func(tx *Job) error {
+		require.Same(t, job, tx)
+		return nil
+	}))
+	require.Empty(t, recorded)
+	require.NoError(t, job.Rollback())
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSON is a json column marshaled from and to T, e.g. JSON[Profile] for a profile jsonb column.
+// Valid is false for NULL.
+type JSON[T any] struct {
+	Val   T
+	Valid bool
+}
+
+func NewJSON[T any](val T) JSON[T] {
+	return JSON[T]{Val: val, Valid: true}
+}
+
+// Scan unmarshals the json column into Val.
+func (t *JSON[T]) Scan(src any) error {
+	var data []byte
+	switch src := src.(type) {
+	case nil:
+		*t = JSON[T]{}
+		return nil
+	case []byte:
+		data = src
+	case string:
+		data = []byte(src)
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, t)
+	}
+
+	var val T
+	if err := json.Unmarshal(data, &val); err != nil {
+		return err
+	}
+	t.Val, t.Valid = val, true
+	return nil
+}
+
+// Value marshals Val to the json column, NULL if not valid.
+func (t JSON[T]) Value() (driver.Value, error) {
+	if t.Valid == false {
+		return nil, nil
+	}
+	return json.Marshal(t.Val)
+}
+
+func (t JSON[T]) MarshalJSON() ([]byte, error) {
+	if t.Valid == false {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Val)
+}
+
+func (t *JSON[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = JSON[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &t.Val); err != nil {
+		return err
+	}
+	t.Valid = true
+	return nil
+}
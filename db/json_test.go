@@ -0,0 +1,48 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testProfile struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+func TestJSONScanValue(t *testing.T) {
+	var profile JSON[testProfile]
+	require.NoError(t, profile.Scan([]byte(`{"name":"kim","tags":["a","b"]}`)))
+	require.Equal(t, NewJSON(testProfile{Name: "kim", Tags: []string{"a", "b"}}), profile)
+
+	val, err := profile.Value()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"kim","tags":["a","b"]}`, string(val.([]byte)))
+
+	require.NoError(t, profile.Scan(nil))
+	require.False(t, profile.Valid)
+	val, err = profile.Value()
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	require.Error(t, profile.Scan(`{"name":`))
+	require.ErrorContains(t, profile.Scan(1), "cannot scan int into")
+}
+
+func TestJSONMarshal(t *testing.T) {
+	row := struct {
+		Profile JSON[testProfile] `json:"profile"`
+		Extra   JSON[[]int]       `json:"extra"`
+	}{Profile: NewJSON(testProfile{Name: "kim"})}
+
+	data, err := json.Marshal(row)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"profile":{"name":"kim","tags":null},"extra":null}`, string(data))
+
+	row.Profile = JSON[testProfile]{}
+	require.NoError(t, json.Unmarshal([]byte(`{"profile":{"name":"lee"},"extra":[1]}`), &row))
+	require.Equal(t, NewJSON(testProfile{Name: "lee"}), row.Profile)
+	require.Equal(t, NewJSON([]int{1}), row.Extra)
+}
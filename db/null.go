@@ -0,0 +1,60 @@
+package db
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// Null is a nullable column of T, generated for nullable columns with the "generic" nullable option.
+// Valid is false for NULL.
+type Null[T any] struct {
+	Val   T
+	Valid bool
+}
+
+func NewNull[T any](val T) Null[T] {
+	return Null[T]{Val: val, Valid: true}
+}
+
+// Ptr returns a pointer to Val, nil for NULL.
+func (t Null[T]) Ptr() *T {
+	if t.Valid == false {
+		return nil
+	}
+	return &t.Val
+}
+
+// Scan converts the column into Val as database/sql converts scan destinations.
+func (t *Null[T]) Scan(src any) error {
+	var null sql.Null[T]
+	if err := null.Scan(src); err != nil {
+		return err
+	}
+	t.Val, t.Valid = null.V, null.Valid
+	return nil
+}
+
+// Value returns Val, NULL if not valid.
+func (t Null[T]) Value() (driver.Value, error) {
+	return sql.Null[T]{V: t.Val, Valid: t.Valid}.Value()
+}
+
+func (t Null[T]) MarshalJSON() ([]byte, error) {
+	if t.Valid == false {
+		return []byte("null"), nil
+	}
+	return json.Marshal(t.Val)
+}
+
+func (t *Null[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Null[T]{}
+		return nil
+	}
+	if err := json.Unmarshal(data, &t.Val); err != nil {
+		return err
+	}
+	t.Valid = true
+	return nil
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNullScanValue(t *testing.T) {
+	var name Null[string]
+	require.NoError(t, name.Scan([]byte("kim")))
+	require.Equal(t, NewNull("kim"), name)
+	val, err := name.Value()
+	require.NoError(t, err)
+	require.Equal(t, "kim", val)
+
+	require.NoError(t, name.Scan(nil))
+	require.False(t, name.Valid)
+	require.Nil(t, name.Ptr())
+	val, err = name.Value()
+	require.NoError(t, err)
+	require.Nil(t, val)
+
+	var age Null[uint32]
+	require.NoError(t, age.Scan(int64(20)))
+	require.Equal(t, uint32(20), *age.Ptr())
+
+	var role Null[testRole]
+	require.NoError(t, role.Scan("owner"))
+	require.Equal(t, NewNull(testRole("owner")), role)
+	require.Error(t, role.Scan("admin"))
+	val, err = role.Value()
+	require.NoError(t, err)
+	require.Equal(t, "owner", val)
+
+	now := time.Now()
+	var at Null[time.Time]
+	require.NoError(t, at.Scan(now))
+	require.Equal(t, now, at.Val)
+}
+
+func TestNullMarshal(t *testing.T) {
+	row := struct {
+		Name Null[string] `json:"name"`
+		Age  Null[int32]  `json:"age"`
+	}{Name: NewNull("kim")}
+
+	data, err := json.Marshal(row)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"name":"kim","age":null}`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`{"name":null,"age":3}`), &row))
+	require.Equal(t, Null[string]{}, row.Name)
+	require.Equal(t, NewNull(int32(3)), row.Age)
+}
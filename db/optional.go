@@ -0,0 +1,299 @@
+package db
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// OptionalArg is the arg of an optional WHERE predicate or SET assignment, which is dropped when Val is nil.
+type OptionalArg struct{ Val any }
+
+// NewOptionalArg returns the optional arg of v, nil if v is nil.
+func NewOptionalArg[T any](v *T) OptionalArg {
+	if v == nil {
+		return OptionalArg{}
+	}
+	return OptionalArg{Val: *v}
+}
+
+// keywords ending a WHERE clause
+var whereEnds = []string{"GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR", "RETURNING", "UNION", "INTERSECT", "EXCEPT"}
+
+// keywords ending the SET clause of an update
+var setEnds = []string{"FROM", "WHERE", "ORDER", "LIMIT", "RETURNING"}
+
+// ErrOptionalOr is returned by OptionalWhere for a nil OptionalArg in a WHERE with a top level OR.
+// Dropping an ORed predicate narrows the result and dropping the whole OR widens it, put the ORs in parentheses.
+var ErrOptionalOr = errors.New("optional arg is in a WHERE with a top level OR")
+
+// OptionalWhere drops the WHERE predicates bound to a nil OptionalArg and unwraps the other OptionalArgs.
+// The WHERE clause is split at its top level ANDs and only the predicate of a nil arg is dropped, with the AND before it.
+// WHERE is dropped too when no predicate is left, and numbered placeholders ($n) are renumbered.
+// It is ErrOptionalOr when a predicate to drop is in a WHERE with a top level OR.
+func OptionalWhere(query string, args []any) (string, []any, error) {
+	hasNil := false
+	for _, arg := range args {
+		if optional, ok := arg.(OptionalArg); ok && optional.Val == nil {
+			hasNil = true
+			break
+		}
+	}
+	if hasNil == false {
+		return query, unwrapOptional(args), nil
+	}
+
+	where, ok := scanClause(query, "WHERE", whereEnds, false)
+	if ok == false {
+		return query, unwrapOptional(args), nil
+	}
+
+	// predicates with a nil optional arg are dropped
+	var kept strings.Builder
+	dropped := make([][2]int, 0)
+	hasOr := false
+	for _, term := range where.terms {
+		hasOr = hasOr || term.sep == "OR"
+		if hasNilOptional(where.placeholders, term.start, term.end, args) {
+			dropped = append(dropped, [2]int{term.start, term.end})
+			continue
+		}
+		if kept.Len() > 0 {
+			kept.WriteString(" " + term.sep + " ")
+		}
+		kept.WriteString(strings.TrimSpace(query[term.start:term.end]))
+	}
+	if hasOr == true && len(dropped) > 0 {
+		return "", nil, ErrOptionalOr
+	}
+
+	body := query[where.body:where.end]
+	trailing := body[len(strings.TrimRight(body, " \t\r\n")):]
+	var out strings.Builder
+	out.Grow(len(query))
+	if kept.Len() == 0 {
+		out.WriteString(strings.TrimRight(query[:where.start], " \t\r\n"))
+	} else {
+		out.WriteString(query[:where.start])
+		out.WriteString("WHERE ")
+		out.WriteString(kept.String())
+	}
+	out.WriteString(trailing)
+	out.WriteString(query[where.end:])
+
+	query, args = dropArgs(out.String(), where.numbered, where.placeholders, dropped, args)
+	return query, unwrapOptional(args), nil
+}
+
+// ErrNoColumnSet is returned by OptionalSet when every SET assignment of the update is bound to a nil arg.
+var ErrNoColumnSet = errors.New("update sets no column, every field is nil")
+
+// OptionalSet drops the SET assignments of an update bound to a nil OptionalArg, for partial updates.
+// The other OptionalArgs are kept for OptionalWhere, which unwraps them. It is ErrNoColumnSet when no assignment is left.
+func OptionalSet(query string, args []any) (string, []any, error) {
+	set, ok := scanClause(query, "SET", setEnds, true)
+	if ok == false {
+		return query, args, nil
+	}
+
+	// assignments with a nil optional arg are dropped
+	kept := make([]string, 0, len(set.terms))
+	dropped := make([][2]int, 0)
+	for _, term := range set.terms {
+		if hasNilOptional(set.placeholders, term.start, term.end, args) {
+			dropped = append(dropped, [2]int{term.start, term.end})
+			continue
+		}
+		kept = append(kept, strings.TrimSpace(query[term.start:term.end]))
+	}
+	if len(kept) == 0 {
+		return "", nil, ErrNoColumnSet
+	}
+	if len(dropped) == 0 {
+		return query, args, nil
+	}
+
+	body := query[set.body:set.end]
+	var out strings.Builder
+	out.Grow(len(query))
+	out.WriteString(query[:set.body])
+	out.WriteString(" ")
+	out.WriteString(strings.Join(kept, ", "))
+	out.WriteString(body[len(strings.TrimRight(body, " \t\r\n")):])
+	out.WriteString(query[set.end:])
+
+	query, args = dropArgs(out.String(), set.numbered, set.placeholders, dropped, args)
+	return query, args, nil
+}
+
+// argPos is a placeholder at pos bound to args[idx]
+type argPos struct{ pos, idx int }
+
+// clause is a top level clause of a query, from its keyword to the next clause, split into terms.
+type clause struct {
+	start, body, end int // keyword, body after the keyword, end of the body
+	terms            []clauseTerm
+	placeholders     []argPos // every placeholder of the query
+	numbered         bool
+}
+
+// clauseTerm is query[start:end], sep is the separator before it, empty for the first term.
+type clauseTerm struct {
+	start, end int
+	sep        string
+}
+
+// scanClause finds the first top level clause starting with keyword and ending at one of ends or ';'.
+// The body is split at its top level commas (comma) or at its ANDs and ORs, skipping the AND of a BETWEEN.
+func scanClause(query, keyword string, ends []string, comma bool) (c clause, ok bool) {
+	c.numbered = hasNumberedArg(query)
+	c.start, c.end = -1, -1
+	seps := make([]clauseTerm, 0)
+	depth, positional, between := 0, 0, false
+	for i := 0; i < len(query); i++ {
+		if end := SkipQuoted(query, i); end > i {
+			i = end - 1
+			continue
+		}
+		switch {
+		case query[i] == '(':
+			depth++
+		case query[i] == ')':
+			depth--
+		case c.numbered && query[i] == '$':
+			if n, _ := numberedArg(query, i); n > 0 {
+				c.placeholders = append(c.placeholders, argPos{i, n - 1})
+			}
+		case c.numbered == false && query[i] == '?':
+			c.placeholders = append(c.placeholders, argPos{i, positional})
+			positional++
+		}
+		if depth != 0 || c.end != -1 {
+			continue
+		}
+		switch {
+		case c.start == -1:
+			if isKeywordAt(query, i, keyword) {
+				c.start, c.body = i, i+len(keyword)
+				i = c.body - 1
+			}
+		case query[i] == ';':
+			c.end = i
+		case comma == true && query[i] == ',':
+			seps = append(seps, clauseTerm{start: i, end: i + 1, sep: ","})
+		case comma == false && isKeywordAt(query, i, "BETWEEN"):
+			between = true
+		case comma == false && isKeywordAt(query, i, "AND"):
+			if between == true {
+				between = false
+			} else {
+				seps = append(seps, clauseTerm{start: i, end: i + len("AND"), sep: "AND"})
+			}
+		case comma == false && isKeywordAt(query, i, "OR"):
+			seps = append(seps, clauseTerm{start: i, end: i + len("OR"), sep: "OR"})
+		default:
+			for _, end := range ends {
+				if isKeywordAt(query, i, end) {
+					c.end = i
+					break
+				}
+			}
+		}
+	}
+	if c.start == -1 {
+		return c, false
+	}
+	if c.end == -1 {
+		c.end = len(query)
+	}
+
+	start, sep := c.body, ""
+	for _, s := range seps {
+		c.terms = append(c.terms, clauseTerm{start: start, end: s.start, sep: sep})
+		start, sep = s.end, s.sep
+	}
+	c.terms = append(c.terms, clauseTerm{start: start, end: c.end, sep: sep})
+	return c, true
+}
+
+// hasNilOptional reports whether a placeholder between start and end is bound to a nil OptionalArg.
+func hasNilOptional(placeholders []argPos, start, end int, args []any) bool {
+	for _, p := range placeholders {
+		if p.pos >= start && p.pos < end && p.idx < len(args) {
+			if optional, ok := args[p.idx].(OptionalArg); ok && optional.Val == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// dropArgs returns the args of the placeholders outside the dropped ranges of the original query,
+// and renumbers the numbered placeholders of the rewritten query in order.
+func dropArgs(query string, numbered bool, placeholders []argPos, dropped [][2]int, args []any) (string, []any) {
+	isDropped := func(pos int) bool {
+		for _, r := range dropped {
+			if pos >= r[0] && pos < r[1] {
+				return true
+			}
+		}
+		return false
+	}
+	if numbered == false {
+		left := make([]any, 0, len(args))
+		for _, p := range placeholders {
+			if isDropped(p.pos) == false && p.idx < len(args) {
+				left = append(left, args[p.idx])
+			}
+		}
+		return query, left
+	}
+
+	used := make([]bool, len(args))
+	for _, p := range placeholders {
+		if isDropped(p.pos) == false && p.idx < len(args) {
+			used[p.idx] = true
+		}
+	}
+	renumber := make([]int, len(args))
+	left := make([]any, 0, len(args))
+	for idx, arg := range args {
+		if used[idx] == true {
+			left = append(left, arg)
+			renumber[idx] = len(left)
+		}
+	}
+	return renumberArgs(query, renumber), left
+}
+
+// renumberArgs rewrites each $n placeholder to $renumber[n-1].
+func renumberArgs(query string, renumber []int) string {
+	var out strings.Builder
+	out.Grow(len(query))
+	for i := 0; i < len(query); {
+		if end := SkipQuoted(query, i); end > i {
+			out.WriteString(query[i:end])
+			i = end
+			continue
+		}
+		n, end := numberedArg(query, i)
+		if n == 0 || n > len(renumber) {
+			out.WriteByte(query[i])
+			i++
+			continue
+		}
+		out.WriteString("$" + strconv.Itoa(renumber[n-1]))
+		i = end
+	}
+	return out.String()
+}
+
+// unwrapOptional replaces the OptionalArgs in args with their values.
+func unwrapOptional(args []any) []any {
+	for i, arg := range args {
+		if optional, ok := arg.(OptionalArg); ok {
+			args[i] = optional.Val
+		}
+	}
+	return args
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptionalWhere(t *testing.T) {
+	name, age := "kim", int32(20)
+	for _, test := range []struct {
+		query    string
+		args     []any
+		expected string
+		left     []any
+	}{
+		{
+			"SELECT * FROM users WHERE tenant_id = ? AND name = ? AND age > ? ORDER BY id",
+			[]any{int64(1), NewOptionalArg(&name), NewOptionalArg[int32](nil)},
+			"SELECT * FROM users WHERE tenant_id = ? AND name = ? ORDER BY id",
+			[]any{int64(1), "kim"},
+		},
+		{
+			"SELECT * FROM users WHERE name = ? AND age > ? LIMIT 10",
+			[]any{NewOptionalArg[string](nil), NewOptionalArg[int32](nil)},
+			"SELECT * FROM users LIMIT 10",
+			[]any{},
+		},
+		{
+			"SELECT * FROM users WHERE name = $1 AND age BETWEEN $2 AND 30 AND tenant_id = $3",
+			[]any{NewOptionalArg[string](nil), NewOptionalArg(&age), int64(1)},
+			"SELECT * FROM users WHERE age BETWEEN $1 AND 30 AND tenant_id = $2",
+			[]any{int32(20), int64(1)},
+		},
+		{
+			"UPDATE users SET name = $1 WHERE (age > $2 OR age IS NULL) AND id IN (SELECT user_id FROM t WHERE c = 'AND') RETURNING id",
+			[]any{"lee", NewOptionalArg[int32](nil)},
+			"UPDATE users SET name = $1 WHERE id IN (SELECT user_id FROM t WHERE c = 'AND') RETURNING id",
+			[]any{"lee"},
+		},
+		{
+			// a top level OR is kept when no arg is nil
+			"DELETE FROM users WHERE name = ? OR age > ?",
+			[]any{NewOptionalArg(&name), NewOptionalArg(&age)},
+			"DELETE FROM users WHERE name = ? OR age > ?",
+			[]any{"kim", int32(20)},
+		},
+		{
+			"SELECT * FROM users WHERE name = ?",
+			[]any{NewOptionalArg(&name)},
+			"SELECT * FROM users WHERE name = ?",
+			[]any{"kim"},
+		},
+	} {
+		query, args, err := OptionalWhere(test.query, test.args)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, query)
+		require.Equal(t, test.left, args)
+	}
+
+	// dropping an ORed predicate would change the rows of the others
+	for _, test := range []struct {
+		query string
+		args  []any
+	}{
+		{"DELETE FROM users WHERE name = ? OR age > ?", []any{NewOptionalArg(&name), NewOptionalArg[int32](nil)}},
+		{"SELECT * FROM users WHERE tenant_id = ? AND name = ? OR email = ?", []any{int64(1), NewOptionalArg[string](nil), "a@b.c"}},
+	} {
+		_, _, err := OptionalWhere(test.query, test.args)
+		require.ErrorIs(t, err, ErrOptionalOr, test.query)
+	}
+}
+
+func TestOptionalSet(t *testing.T) {
+	name, age := "kim", int32(20)
+	for _, test := range []struct {
+		query    string
+		args     []any
+		expected string
+		left     []any
+	}{
+		{
+			"UPDATE users SET name = ?, age = ?, updated_at = NOW() WHERE id = ?",
+			[]any{NewOptionalArg[string](nil), NewOptionalArg(&age), int64(1)},
+			"UPDATE users SET age = ?, updated_at = NOW() WHERE id = ?",
+			[]any{OptionalArg{Val: int32(20)}, int64(1)},
+		},
+		{
+			"UPDATE users SET name = $1, age = $2, score = GREATEST($3, 0) WHERE id = $4 RETURNING id",
+			[]any{NewOptionalArg(&name), NewOptionalArg[int32](nil), NewOptionalArg[int32](nil), int64(1)},
+			"UPDATE users SET name = $1 WHERE id = $2 RETURNING id",
+			[]any{OptionalArg{Val: "kim"}, int64(1)},
+		},
+		{
+			"UPDATE users SET name = ? WHERE id = ?",
+			[]any{NewOptionalArg(&name), int64(1)},
+			"UPDATE users SET name = ? WHERE id = ?",
+			[]any{OptionalArg{Val: "kim"}, int64(1)},
+		},
+	} {
+		query, args, err := OptionalSet(test.query, test.args)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, query)
+		require.Equal(t, test.left, args)
+	}
+
+	_, _, err := OptionalSet("UPDATE users SET name = ?, age = ? WHERE id = ?", []any{NewOptionalArg[string](nil), NewOptionalArg[int32](nil), int64(1)})
+	require.ErrorIs(t, err, ErrNoColumnSet)
+
+	// the unwrapped args are bound after OptionalWhere
+	query, args, err := OptionalSet("UPDATE users SET name = $1, age = $2 WHERE id = $3 AND name = $4", []any{NewOptionalArg(&name), NewOptionalArg[int32](nil), int64(1), NewOptionalArg[string](nil)})
+	require.NoError(t, err)
+	query, args, err = OptionalWhere(query, args)
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2", query)
+	require.Equal(t, []any{"kim", int64(1)}, args)
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Direction is the sort direction of a dynamic ORDER BY.
+type Direction string
+
+const (
+	Asc  Direction = "ASC"
+	Desc Direction = "DESC"
+)
+
+// keywords after an ORDER BY clause
+var orderEnds = []string{"LIMIT", "OFFSET", "FETCH", "FOR"}
+
+// OrderBy sets the top level ORDER BY of the query to the column and direction, ascending if empty.
+// An existing ORDER BY is replaced, otherwise it is added before LIMIT, OFFSET, FETCH and FOR.
+// The column is a generated OrderBy enum, so only its columns are ever written into the sql.
+func OrderBy[T Enum](query string, column T, direction Direction) (string, error) {
+	if column.Valid() == false {
+		return "", fmt.Errorf("invalid %T value %q", column, string(column))
+	}
+	switch Direction(strings.ToUpper(string(direction))) {
+	case "", Asc:
+		direction = Asc
+	case Desc:
+		direction = Desc
+	default:
+		return "", fmt.Errorf("invalid direction %q", string(direction))
+	}
+	clause := "ORDER BY " + string(column) + " " + string(direction)
+
+	order, end, depth := -1, -1, 0
+	for i := 0; i < len(query) && end == -1; i++ {
+		if skip := SkipQuoted(query, i); skip > i {
+			i = skip - 1
+			continue
+		}
+		switch {
+		case query[i] == '(':
+			depth++
+		case query[i] == ')':
+			depth--
+		case depth != 0:
+		case query[i] == ';':
+			end = i
+		case order == -1 && isKeywordAt(query, i, "ORDER"):
+			order = i
+		default:
+			for _, keyword := range orderEnds {
+				if isKeywordAt(query, i, keyword) {
+					end = i
+					break
+				}
+			}
+		}
+	}
+	if end == -1 {
+		end = len(query)
+	}
+
+	if order != -1 {
+		body := query[order:end]
+		return query[:order] + clause + body[len(strings.TrimRight(body, " \t\r\n")):] + query[end:], nil
+	}
+	before := strings.TrimRight(query[:end], " \t\r\n")
+	space := query[len(before):end]
+	if space == "" && end < len(query) && query[end] != ';' {
+		space = " "
+	}
+	return before + " " + clause + space + query[end:], nil
+}
@@ -0,0 +1,35 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testSort string
+
+func (t testSort) Valid() bool { return t == "name" || t == "u.created_at" }
+
+func TestOrderBy(t *testing.T) {
+	for _, test := range []struct {
+		query     string
+		column    testSort
+		direction Direction
+		expected  string
+	}{
+		{"SELECT * FROM users", "name", Asc, "SELECT * FROM users ORDER BY name ASC"},
+		{"SELECT * FROM users ORDER BY id LIMIT ?", "name", Desc, "SELECT * FROM users ORDER BY name DESC LIMIT ?"},
+		{"SELECT * FROM users u WHERE id IN (SELECT id FROM t ORDER BY id LIMIT 3)\nLIMIT 10 OFFSET 20", "u.created_at", "", "SELECT * FROM users u WHERE id IN (SELECT id FROM t ORDER BY id LIMIT 3) ORDER BY u.created_at ASC\nLIMIT 10 OFFSET 20"},
+		{"SELECT * FROM users WHERE memo = 'ORDER BY x' FOR UPDATE;", "name", "desc", "SELECT * FROM users WHERE memo = 'ORDER BY x' ORDER BY name DESC FOR UPDATE;"},
+		{"SELECT * FROM users ORDER BY id\n", "name", Asc, "SELECT * FROM users ORDER BY name ASC\n"},
+	} {
+		query, err := OrderBy(test.query, test.column, test.direction)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, query)
+	}
+
+	_, err := OrderBy("SELECT * FROM users", testSort("id; DROP TABLE users"), Asc)
+	require.Error(t, err)
+	_, err = OrderBy("SELECT * FROM users", testSort("name"), Direction("ASC; DROP TABLE users"))
+	require.Error(t, err)
+}
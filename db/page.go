@@ -0,0 +1,263 @@
+package db
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Keyset is the key of a keyset (cursor) paginated select, the columns of its ORDER BY then the primary key.
+// A page continues after the key of the last row of the previous page, (a, b) > (?, ?) or < if descending.
+type Keyset struct {
+	Columns  []string // key columns in ORDER BY order, ending with the primary key columns so rows never tie
+	Desc     bool     // every key column is sorted descending
+	Numbered bool     // postgres style $n placeholders
+}
+
+// NewKeyset returns the keyset of the ORDER BY of a select followed by the primary key columns it does not order by,
+// the primary key ascending if it has no ORDER BY. The primary key breaks the ties of the ORDER BY columns,
+// without it the rows of the same key at a page boundary would be skipped.
+// Every ORDER BY item must be a column sorted in the same direction, and the select can not have a LIMIT, OFFSET or set operation.
+func NewKeyset(query string, primaryKey []string, numbered bool) (Keyset, error) {
+	t := Keyset{Numbered: numbered}
+	clauses := topClauses(query)
+	for _, keyword := range []string{"LIMIT", "OFFSET", "FETCH", "UNION", "INTERSECT", "EXCEPT"} {
+		if _, ok := clauses[keyword]; ok {
+			return t, fmt.Errorf("paginated select can not have %s", keyword)
+		}
+	}
+	if len(primaryKey) == 0 {
+		return t, fmt.Errorf("paginated select needs a primary key to order the rows uniquely")
+	}
+
+	order, ok := clauses["ORDER"]
+	if ok == false {
+		t.Columns = primaryKey
+		return t, nil
+	}
+
+	fields := strings.Fields(order)
+	if len(fields) < 2 || strings.EqualFold(fields[1], "BY") == false {
+		return t, fmt.Errorf("invalid ORDER BY %q", order)
+	}
+	for i, item := range strings.Split(strings.Join(fields[2:], " "), ",") {
+		words := strings.Fields(item)
+		desc := false
+		switch {
+		case len(words) == 1:
+		case len(words) == 2 && strings.EqualFold(words[1], "ASC"):
+		case len(words) == 2 && strings.EqualFold(words[1], "DESC"):
+			desc = true
+		default:
+			return t, fmt.Errorf("paginated select can only order by columns, not %q", strings.TrimSpace(item))
+		}
+		if isColumnName(words[0]) == false {
+			return t, fmt.Errorf("paginated select can only order by columns, not %q", words[0])
+		}
+		if i > 0 && desc != t.Desc {
+			return t, fmt.Errorf("paginated select must order every column in the same direction")
+		}
+		t.Desc = desc
+		t.Columns = append(t.Columns, words[0])
+	}
+
+	// primary key tiebreaker, qualified like the first ORDER BY column
+	qualifier := t.Columns[0][:strings.LastIndex(t.Columns[0], ".")+1]
+	for _, pk := range primaryKey {
+		if isOrdered(t.Columns, pk) == false {
+			t.Columns = append(t.Columns, qualifier+pk)
+		}
+	}
+	return t, nil
+}
+
+// Page limits the select to a page of limit rows after the cursor, the first page if the cursor is empty.
+// The keyset predicate is added to the WHERE, the key columns the ORDER BY of the select misses
+// (every key column if it has none), and LIMIT limit+1, the extra row telling there is a next page.
+// The cursor values are decoded into keys, pointers to values of the key column types, and bound as args
+// so times and custom types are written the way the column stores them.
+func (t Keyset) Page(query string, args []any, cursor string, limit int, keys ...any) (string, []any, error) {
+	if limit <= 0 {
+		return "", nil, fmt.Errorf("page limit %d is not positive", limit)
+	}
+	query = strings.TrimRight(query, " \t\r\n;")
+	clauses := topClausePos(query)
+
+	// ORDER BY and LIMIT, before FOR UPDATE / SHARE
+	end := len(query)
+	if pos, ok := clauses["FOR"]; ok {
+		end = pos
+	}
+	direction := Asc
+	if t.Desc == true {
+		direction = Desc
+	}
+	var ordered, items []string
+	if pos, ok := clauses["ORDER"]; ok {
+		fields := strings.Fields(query[pos:end])
+		for _, item := range strings.Split(strings.Join(fields[min(2, len(fields)):], " "), ",") {
+			if words := strings.Fields(item); len(words) > 0 {
+				ordered = append(ordered, words[0])
+			}
+		}
+	}
+	for _, column := range t.Columns {
+		if isOrdered(ordered, column) == false {
+			items = append(items, column+" "+string(direction))
+		}
+	}
+	tail := " LIMIT " + strconv.Itoa(limit+1)
+	switch {
+	case len(items) > 0 && len(ordered) > 0:
+		tail = ", " + strings.Join(items, ", ") + tail
+	case len(items) > 0:
+		tail = " ORDER BY " + strings.Join(items, ", ") + tail
+	}
+	query = strings.TrimRight(query[:end], " \t\r\n") + tail + strings.TrimRight(" "+query[end:], " ")
+	if cursor == "" {
+		return query, args, nil
+	}
+
+	// keyset predicate
+	if err := t.decode(cursor, keys); err != nil {
+		return "", nil, err
+	}
+	placeholders := make([]string, len(keys))
+	for i := range keys {
+		placeholders[i] = "?"
+		if t.Numbered == true {
+			placeholders[i] = "$" + strconv.Itoa(len(args)+i+1)
+		}
+	}
+	op := ">"
+	if t.Desc == true {
+		op = "<"
+	}
+	predicate := fmt.Sprintf("(%s) %s (%s)", strings.Join(t.Columns, ", "), op, strings.Join(placeholders, ", "))
+
+	clauses = topClausePos(query)
+	pos := len(query)
+	for _, keyword := range []string{"GROUP", "HAVING", "WINDOW", "ORDER"} {
+		if p, ok := clauses[keyword]; ok && p < pos {
+			pos = p
+		}
+	}
+	before := strings.TrimRight(query[:pos], " \t\r\n")
+	if where, ok := clauses["WHERE"]; ok {
+		body := strings.TrimSpace(query[where+len("WHERE") : len(before)])
+		before = query[:where] + "WHERE (" + body + ") AND " + predicate
+	} else {
+		before += " WHERE " + predicate
+	}
+	return before + " " + query[pos:], append(args, keys...), nil
+}
+
+// Cursor returns the cursor of the page after the row with the key values, base64 of the json values.
+func (t Keyset) Cursor(values ...any) (string, error) {
+	if len(values) != len(t.Columns) {
+		return "", fmt.Errorf("cursor has %d values for %d key columns", len(values), len(t.Columns))
+	}
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decode sets the keys to the key values of a cursor.
+func (t Keyset) decode(cursor string, keys []any) error {
+	if len(keys) != len(t.Columns) {
+		return fmt.Errorf("page has %d keys for %d key columns", len(keys), len(t.Columns))
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return fmt.Errorf("invalid cursor")
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil || len(values) != len(t.Columns) {
+		return fmt.Errorf("invalid cursor")
+	}
+	for i, value := range values {
+		if err := json.Unmarshal(value, keys[i]); err != nil {
+			return fmt.Errorf("invalid cursor")
+		}
+	}
+	return nil
+}
+
+// keywords of the clauses of a select
+var selectClauses = []string{"WHERE", "GROUP", "HAVING", "WINDOW", "ORDER", "LIMIT", "OFFSET", "FETCH", "FOR", "UNION", "INTERSECT", "EXCEPT"}
+
+// topClausePos returns where each clause of the select starts, outside parentheses, quotes and comments.
+func topClausePos(query string) map[string]int {
+	clauses := make(map[string]int)
+	depth := 0
+	for i := 0; i < len(query); i++ {
+		if end := SkipQuoted(query, i); end > i {
+			i = end - 1
+			continue
+		}
+		switch {
+		case query[i] == '(':
+			depth++
+		case query[i] == ')':
+			depth--
+		case depth == 0:
+			for _, keyword := range selectClauses {
+				if _, ok := clauses[keyword]; ok == false && isKeywordAt(query, i, keyword) {
+					clauses[keyword] = i
+					break
+				}
+			}
+		}
+	}
+	return clauses
+}
+
+// topClauses returns the text of each clause of the select, up to the next clause.
+func topClauses(query string) map[string]string {
+	query = strings.TrimRight(query, " \t\r\n;")
+	positions := topClausePos(query)
+	clauses := make(map[string]string, len(positions))
+	for keyword, start := range positions {
+		end := len(query)
+		for _, pos := range positions {
+			if pos > start && pos < end {
+				end = pos
+			}
+		}
+		clauses[keyword] = strings.TrimSpace(query[start:end])
+	}
+	return clauses
+}
+
+// isOrdered reports whether the column is one of the ordered columns, compared without the table.
+func isOrdered(ordered []string, column string) bool {
+	column = column[strings.LastIndex(column, ".")+1:]
+	for _, c := range ordered {
+		if c[strings.LastIndex(c, ".")+1:] == column {
+			return true
+		}
+	}
+	return false
+}
+
+// isColumnName reports whether s is a column or table.column of identifier characters.
+func isColumnName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, part := range strings.Split(s, ".") {
+		if part == "" {
+			return false
+		}
+		for i := 0; i < len(part); i++ {
+			if IsIdentChar(part[i]) == false {
+				return false
+			}
+		}
+	}
+	return true
+}
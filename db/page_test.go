@@ -0,0 +1,99 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeyset(t *testing.T) {
+	for _, test := range []struct {
+		query    string
+		expected Keyset
+	}{
+		{"SELECT * FROM users", Keyset{Columns: []string{"id"}}},
+		{"SELECT * FROM users ORDER BY created_at, id", Keyset{Columns: []string{"created_at", "id"}}},
+		{"SELECT * FROM users u WHERE id IN (SELECT id FROM t LIMIT 3) ORDER BY u.created_at DESC, u.id desc;", Keyset{Columns: []string{"u.created_at", "u.id"}, Desc: true}},
+		// the primary key breaks the ties of a non unique ORDER BY
+		{"SELECT * FROM users ORDER BY name", Keyset{Columns: []string{"name", "id"}}},
+		{"SELECT * FROM users u ORDER BY u.name DESC", Keyset{Columns: []string{"u.name", "u.id"}, Desc: true}},
+	} {
+		keyset, err := NewKeyset(test.query, []string{"id"}, false)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, keyset)
+	}
+
+	for _, query := range []string{
+		"SELECT * FROM users LIMIT 10",
+		"SELECT * FROM users ORDER BY id OFFSET 10",
+		"SELECT * FROM users ORDER BY created_at DESC, id",
+		"SELECT * FROM users ORDER BY lower(name)",
+		"SELECT id FROM users UNION SELECT id FROM admins",
+	} {
+		_, err := NewKeyset(query, []string{"id"}, false)
+		require.Error(t, err, query)
+	}
+	_, err := NewKeyset("SELECT * FROM users", nil, false)
+	require.Error(t, err)
+	_, err = NewKeyset("SELECT * FROM users ORDER BY name", nil, false)
+	require.Error(t, err)
+}
+
+func TestKeysetPage(t *testing.T) {
+	keyset := Keyset{Columns: []string{"created_at", "id"}}
+	query, args, err := keyset.Page("SELECT * FROM users WHERE name = ? OR age > ?", []any{"a", 3}, "", 10)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM users WHERE name = ? OR age > ? ORDER BY created_at ASC, id ASC LIMIT 11", query)
+	require.Equal(t, []any{"a", 3}, args)
+
+	// the keys are decoded back to the key column types, a time is bound as a time and not as json text
+	createdAt := TextTime{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+	cursor, err := keyset.Cursor(createdAt, int64(42))
+	require.NoError(t, err)
+	after := struct {
+		CreatedAt TextTime
+		Id        int64
+	}{}
+	query, args, err = keyset.Page("SELECT * FROM users WHERE name = ? OR age > ?", []any{"a", 3}, cursor, 10, &after.CreatedAt, &after.Id)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM users WHERE (name = ? OR age > ?) AND (created_at, id) > (?, ?) ORDER BY created_at ASC, id ASC LIMIT 11", query)
+	require.Equal(t, []any{"a", 3, &after.CreatedAt, &after.Id}, args)
+	require.True(t, createdAt.Equal(after.CreatedAt.Time))
+	require.Equal(t, int64(42), after.Id)
+
+	// sql.Null types marshal to an object and decode back
+	keyset = Keyset{Columns: []string{"id"}, Desc: true, Numbered: true}
+	cursor, err = keyset.Cursor(sql.NullInt64{Int64: 7, Valid: true})
+	require.NoError(t, err)
+	var id sql.NullInt64
+	query, args, err = keyset.Page("SELECT * FROM users GROUP BY id ORDER BY id DESC FOR UPDATE;", nil, cursor, 5, &id)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM users WHERE (id) < ($1) GROUP BY id ORDER BY id DESC LIMIT 6 FOR UPDATE", query)
+	require.Equal(t, []any{&id}, args)
+	require.Equal(t, sql.NullInt64{Int64: 7, Valid: true}, id)
+
+	_, _, err = keyset.Page("SELECT * FROM users", nil, "", 0, &id)
+	require.Error(t, err)
+	_, _, err = keyset.Page("SELECT * FROM users", nil, "not a cursor", 5, &id)
+	require.Error(t, err)
+	_, _, err = keyset.Page("SELECT * FROM users", nil, cursor, 5)
+	require.Error(t, err)
+	var name string
+	_, _, err = keyset.Page("SELECT * FROM users", nil, cursor, 5, &name)
+	require.Error(t, err)
+	_, err = keyset.Cursor(1, 2)
+	require.Error(t, err)
+
+	// the primary key tiebreaker is appended to the ORDER BY of the select
+	keyset, err = NewKeyset("SELECT id, name FROM users u ORDER BY u.name", []string{"id"}, false)
+	require.NoError(t, err)
+	cursor, err = keyset.Cursor("bob", int64(3))
+	require.NoError(t, err)
+	var userId int64
+	query, args, err = keyset.Page("SELECT id, name FROM users u ORDER BY u.name", nil, cursor, 5, &name, &userId)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT id, name FROM users u WHERE (u.name, u.id) > (?, ?) ORDER BY u.name, u.id ASC LIMIT 6", query)
+	require.Equal(t, []any{&name, &userId}, args)
+}
@@ -0,0 +1,172 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RangeBound is the element type of a postgres range: int4range, int8range, numrange, tsrange, tstzrange and daterange.
+// numrange bounds are float64, or string with the decimal option set to string.
+type RangeBound interface {
+	int32 | int64 | float64 | string | time.Time
+}
+
+// Range is a postgres range of T, e.g. [1,10) as Range[int32]{Lower: 1, Upper: 10, LowerInc: true}.
+// An unbounded side has LowerInf / UpperInf set, an empty range has Empty set.
+type Range[T RangeBound] struct {
+	Lower, Upper       T
+	LowerInc, UpperInc bool // the bound is included, [ or ]
+	LowerInf, UpperInf bool // no bound on the side
+	Empty              bool
+}
+
+// NewRange returns the range [lower, upper), the canonical form of discrete ranges.
+func NewRange[T RangeBound](lower, upper T) Range[T] {
+	return Range[T]{Lower: lower, Upper: upper, LowerInc: true}
+}
+
+// rangeTimeLayouts are the layouts of range time bounds as postgres writes them
+var rangeTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999-07",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// Scan parses the range text, e.g. [1,10), ["2024-01-01 00:00:00+00",) or empty.
+func (t *Range[T]) Scan(src any) error {
+	var text string
+	switch src := src.(type) {
+	case []byte:
+		text = string(src)
+	case string:
+		text = src
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, t)
+	}
+
+	r := Range[T]{}
+	if strings.EqualFold(text, "empty") {
+		r.Empty = true
+		*t = r
+		return nil
+	}
+	if len(text) < 3 || strings.IndexByte("[(", text[0]) == -1 || strings.IndexByte("])", text[len(text)-1]) == -1 {
+		return fmt.Errorf("invalid range %q", text)
+	}
+	lower, upper, ok := splitRange(text[1 : len(text)-1])
+	if ok == false {
+		return fmt.Errorf("invalid range %q", text)
+	}
+
+	r.LowerInc, r.UpperInc = text[0] == '[', text[len(text)-1] == ']'
+	var err error
+	if r.LowerInf = lower == ""; r.LowerInf == false {
+		if r.Lower, err = parseRangeBound[T](lower); err != nil {
+			return err
+		}
+	}
+	if r.UpperInf = upper == ""; r.UpperInf == false {
+		if r.Upper, err = parseRangeBound[T](upper); err != nil {
+			return err
+		}
+	}
+	*t = r
+	return nil
+}
+
+// Value formats the range text.
+func (t Range[T]) Value() (driver.Value, error) {
+	if t.Empty == true {
+		return "empty", nil
+	}
+	var b strings.Builder
+	if t.LowerInc == true && t.LowerInf == false {
+		b.WriteByte('[')
+	} else {
+		b.WriteByte('(')
+	}
+	if t.LowerInf == false {
+		b.WriteString(formatRangeBound(t.Lower))
+	}
+	b.WriteByte(',')
+	if t.UpperInf == false {
+		b.WriteString(formatRangeBound(t.Upper))
+	}
+	if t.UpperInc == true && t.UpperInf == false {
+		b.WriteByte(']')
+	} else {
+		b.WriteByte(')')
+	}
+	return b.String(), nil
+}
+
+// splitRange splits the bounds of a range at the comma outside quotes, and unquotes them.
+func splitRange(s string) (lower, upper string, ok bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case s[i] == '"':
+			quoted = !quoted
+		case s[i] == ',' && quoted == false:
+			return unquoteRangeBound(s[:i]), unquoteRangeBound(s[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+func unquoteRangeBound(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+	s = strings.ReplaceAll(s, `""`, `"`)
+	return strings.ReplaceAll(s, `\`, "")
+}
+
+func parseRangeBound[T RangeBound](s string) (bound T, err error) {
+	switch p := any(&bound).(type) {
+	case *int32:
+		n, err := strconv.ParseInt(s, 10, 32)
+		*p = int32(n)
+		return bound, err
+	case *int64:
+		*p, err = strconv.ParseInt(s, 10, 64)
+		return bound, err
+	case *float64:
+		*p, err = strconv.ParseFloat(s, 64)
+		return bound, err
+	case *string:
+		*p = s
+		return bound, nil
+	case *time.Time:
+		for _, layout := range rangeTimeLayouts {
+			if *p, err = time.Parse(layout, s); err == nil {
+				return bound, nil
+			}
+		}
+		return bound, fmt.Errorf("invalid range time bound %q", s)
+	}
+	return bound, fmt.Errorf("unsupported range bound %T", bound)
+}
+
+func formatRangeBound(bound any) string {
+	switch bound := bound.(type) {
+	case int32:
+		return strconv.FormatInt(int64(bound), 10)
+	case int64:
+		return strconv.FormatInt(bound, 10)
+	case float64:
+		return strconv.FormatFloat(bound, 'g', -1, 64)
+	case string:
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(bound) + `"`
+	case time.Time:
+		return `"` + bound.Format(rangeTimeLayouts[0]) + `"`
+	}
+	return fmt.Sprint(bound)
+}
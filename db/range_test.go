@@ -0,0 +1,60 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeScanValue(t *testing.T) {
+	var ints Range[int32]
+	require.NoError(t, ints.Scan([]byte("[1,10)")))
+	require.Equal(t, NewRange[int32](1, 10), ints)
+	val, err := ints.Value()
+	require.NoError(t, err)
+	require.Equal(t, "[1,10)", val)
+
+	var big Range[int64]
+	require.NoError(t, big.Scan("(,5]"))
+	require.Equal(t, Range[int64]{Upper: 5, UpperInc: true, LowerInf: true}, big)
+	val, err = big.Value()
+	require.NoError(t, err)
+	require.Equal(t, "(,5]", val)
+
+	var nums Range[float64]
+	require.NoError(t, nums.Scan("[1.5,)"))
+	require.Equal(t, Range[float64]{Lower: 1.5, LowerInc: true, UpperInf: true}, nums)
+
+	require.NoError(t, nums.Scan("empty"))
+	require.True(t, nums.Empty)
+	val, err = nums.Value()
+	require.NoError(t, err)
+	require.Equal(t, "empty", val)
+
+	var decs Range[string]
+	require.NoError(t, decs.Scan("[0.10,99999999999999999999.5)"))
+	require.Equal(t, NewRange("0.10", "99999999999999999999.5"), decs)
+	val, err = decs.Value()
+	require.NoError(t, err)
+	require.Equal(t, `["0.10","99999999999999999999.5")`, val)
+
+	var times Range[time.Time]
+	require.NoError(t, times.Scan(`["2024-01-01 10:00:00+09","2024-01-02 00:00:00+09")`))
+	require.True(t, times.Lower.Equal(time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)))
+	val, err = times.Value()
+	require.NoError(t, err)
+	require.Equal(t, `["2024-01-01 10:00:00+09:00","2024-01-02 00:00:00+09:00")`, val)
+
+	var again Range[time.Time]
+	require.NoError(t, again.Scan(val))
+	require.True(t, times.Upper.Equal(again.Upper))
+
+	var days Range[time.Time]
+	require.NoError(t, days.Scan("[2024-01-01,2024-02-01)"))
+	require.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), days.Upper)
+
+	require.Error(t, ints.Scan("1,10"))
+	require.Error(t, ints.Scan("[a,10)"))
+	require.Error(t, ints.Scan(1))
+}
@@ -0,0 +1,35 @@
+package db
+
+import "strings"
+
+// SkipQuoted returns the end of the quoted string or comment starting at i, or i.
+func SkipQuoted(query string, i int) int {
+	switch {
+	case query[i] == '\'' || query[i] == '"' || query[i] == '`':
+		if end := strings.IndexByte(query[i+1:], query[i]); end != -1 {
+			return i + 1 + end + 1
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "--"):
+		if end := strings.IndexByte(query[i:], '\n'); end != -1 {
+			return i + end
+		}
+		return len(query)
+	case strings.HasPrefix(query[i:], "/*"):
+		if end := strings.Index(query[i+2:], "*/"); end != -1 {
+			return i + 2 + end + 2
+		}
+		return len(query)
+	}
+	return i
+}
+
+// IsIdentStart reports whether ch can start an unquoted identifier.
+func IsIdentStart(ch byte) bool {
+	return ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// IsIdentChar reports whether ch can be in an unquoted identifier.
+func IsIdentChar(ch byte) bool {
+	return IsIdentStart(ch) || (ch >= '0' && ch <= '9')
+}
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkipQuoted(t *testing.T) {
+	query := "a = 'x?' -- ?\n/* ? */ `b`"
+	require.Equal(t, 0, SkipQuoted(query, 0))
+	require.Equal(t, 8, SkipQuoted(query, 4))
+	require.Equal(t, 13, SkipQuoted(query, 9))
+	require.Equal(t, 21, SkipQuoted(query, 14))
+	require.Equal(t, len(query), SkipQuoted(query, 22))
+	require.Equal(t, len("'open"), SkipQuoted("'open", 0))
+}
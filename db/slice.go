@@ -0,0 +1,112 @@
+package db
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrEmptySlice is returned by ExpandSlice for an empty SliceArg.
+// An empty list has no portable sql, "IN (NULL)" matches no rows but so does "NOT IN (NULL)".
+var ErrEmptySlice = errors.New("slice arg is empty")
+
+// SliceArg is a list bound to a single placeholder, e.g. IN (?).
+// ExpandSlice turns the placeholder into one placeholder per item.
+type SliceArg []any
+
+func NewSliceArg[T any](items []T) SliceArg {
+	arg := make(SliceArg, len(items))
+	for i, item := range items {
+		arg[i] = item
+	}
+	return arg
+}
+
+// ExpandSlice rewrites the placeholder of every SliceArg in args to one placeholder per item
+// and flattens args to match. An empty slice is ErrEmptySlice, check the length before the query.
+// Numbered placeholders ($n) are renumbered; a $n used several times is expanded at each use.
+func ExpandSlice(query string, args []any) (string, []any, error) {
+	hasSlice := false
+	for _, arg := range args {
+		if items, ok := arg.(SliceArg); ok {
+			if len(items) == 0 {
+				return "", nil, ErrEmptySlice
+			}
+			hasSlice = true
+		}
+	}
+	if hasSlice == false {
+		return query, args, nil
+	}
+
+	// first placeholder number of each arg after expansion
+	expanded := make([]any, 0, len(args))
+	start := make([]int, len(args))
+	for i, arg := range args {
+		start[i] = len(expanded) + 1
+		if items, ok := arg.(SliceArg); ok {
+			expanded = append(expanded, items...)
+		} else {
+			expanded = append(expanded, arg)
+		}
+	}
+
+	numbered := hasNumberedArg(query)
+	var out strings.Builder
+	out.Grow(len(query) + 2*len(expanded))
+
+	positional := 0
+	for i := 0; i < len(query); {
+		if end := SkipQuoted(query, i); end > i {
+			out.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		idx, end := -1, i+1
+		switch {
+		case numbered && query[i] == '$':
+			n, next := numberedArg(query, i)
+			idx, end = n-1, max(next, end)
+		case numbered == false && query[i] == '?':
+			idx = positional
+			positional++
+		}
+		if idx < 0 || idx >= len(args) {
+			out.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		count := 1
+		if items, ok := args[idx].(SliceArg); ok {
+			count = len(items)
+		}
+		for n := 0; n < count; n++ {
+			if n > 0 {
+				out.WriteString(", ")
+			}
+			if numbered {
+				out.WriteString("$" + strconv.Itoa(start[idx]+n))
+			} else {
+				out.WriteString("?")
+			}
+		}
+		i = end
+	}
+	return out.String(), expanded, nil
+}
+
+// hasNumberedArg reports whether the query uses postgres style $n placeholders.
+func hasNumberedArg(query string) bool {
+	for i := 0; i < len(query); i++ {
+		if end := SkipQuoted(query, i); end > i {
+			i = end - 1
+			continue
+		}
+		if n, _ := numberedArg(query, i); n > 0 {
+			return true
+		}
+	}
+	return false
+}
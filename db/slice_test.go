@@ -0,0 +1,50 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandSlice(t *testing.T) {
+	for _, test := range []struct {
+		query    string
+		args     []any
+		expected string
+		flat     []any
+	}{
+		{
+			"SELECT * FROM t WHERE a = ? AND id IN (?) AND b = ?",
+			[]any{"a", NewSliceArg([]int{1, 2, 3}), "b"},
+			"SELECT * FROM t WHERE a = ? AND id IN (?, ?, ?) AND b = ?",
+			[]any{"a", 1, 2, 3, "b"},
+		},
+		{
+			"SELECT * FROM t WHERE c = '?' AND id IN (?)",
+			[]any{NewSliceArg([]string{"x"})},
+			"SELECT * FROM t WHERE c = '?' AND id IN (?)",
+			[]any{"x"},
+		},
+		{
+			"SELECT * FROM t WHERE id IN ($2) AND a = $1 AND b = $3 OR parent IN ($2)",
+			[]any{"a", NewSliceArg([]int64{7, 8}), "b"},
+			"SELECT * FROM t WHERE id IN ($2, $3) AND a = $1 AND b = $4 OR parent IN ($2, $3)",
+			[]any{"a", int64(7), int64(8), "b"},
+		},
+	} {
+		query, args, err := ExpandSlice(test.query, test.args)
+		require.NoError(t, err)
+		require.Equal(t, test.expected, query)
+		require.Equal(t, test.flat, args)
+	}
+
+	// no slice arg, nothing to do
+	query, args, err := ExpandSlice("SELECT * FROM t WHERE id = ?", []any{1})
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM t WHERE id = ?", query)
+	require.Equal(t, []any{1}, args)
+
+	// an empty list has no sql matching both IN and NOT IN
+	_, _, err = ExpandSlice("SELECT * FROM t WHERE id NOT IN ($1) AND a = $2", []any{NewSliceArg([]int64{}), "a"})
+	require.ErrorIs(t, err, ErrEmptySlice)
+}
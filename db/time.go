@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Times stored in a database without a time type (sqlite), generated by the time format option.
+// TextTime is ISO8601 text, UnixTime unix epoch seconds and JulianTime a julian day number.
+// Scan accepts any of the formats, Value writes its own.
+type (
+	TextTime   struct{ time.Time }
+	UnixTime   struct{ time.Time }
+	JulianTime struct{ time.Time }
+)
+
+// TextTimeLayout is the layout TextTime writes, ISO8601 as sqlite date and time functions read it
+const TextTimeLayout = "2006-01-02 15:04:05.999999999-07:00"
+
+// textTimeLayouts are the layouts scanned from text, the time values of sqlite date and time functions first
+var textTimeLayouts = []string{
+	TextTimeLayout,
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"15:04:05.999999999",
+	"15:04",
+	time.RFC3339Nano,
+}
+
+// unix epoch as a julian day number
+const julianUnixEpoch = 2440587.5
+
+func (t *TextTime) Scan(src any) (err error) {
+	t.Time, err = scanTime(src)
+	return err
+}
+
+func (t TextTime) Value() (driver.Value, error) {
+	return t.Format(TextTimeLayout), nil
+}
+
+func (t *UnixTime) Scan(src any) (err error) {
+	t.Time, err = scanTime(src)
+	return err
+}
+
+func (t UnixTime) Value() (driver.Value, error) {
+	return t.Unix(), nil
+}
+
+func (t *JulianTime) Scan(src any) (err error) {
+	t.Time, err = scanTime(src)
+	return err
+}
+
+func (t JulianTime) Value() (driver.Value, error) {
+	return float64(t.UnixNano())/float64(24*time.Hour) + julianUnixEpoch, nil
+}
+
+// scanTime converts text, unix epoch seconds (integer) and julian day numbers (real) to a time.
+func scanTime(src any) (time.Time, error) {
+	switch src := src.(type) {
+	case time.Time:
+		return src, nil
+	case int64:
+		return time.Unix(src, 0).UTC(), nil
+	case float64:
+		// sqlite julian days are precise to a millisecond
+		days, frac := math.Modf(src - julianUnixEpoch)
+		return time.Unix(int64(days)*86400, 0).Add(time.Duration(frac * float64(24*time.Hour))).Round(time.Millisecond).UTC(), nil
+	case []byte:
+		return parseTime(string(src))
+	case string:
+		return parseTime(src)
+	}
+	return time.Time{}, fmt.Errorf("cannot scan %T into a time", src)
+}
+
+func parseTime(s string) (time.Time, error) {
+	for _, layout := range textTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	// unix epoch or julian day stored as text
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return scanTime(n)
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return scanTime(f)
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a time", s)
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFormats(t *testing.T) {
+	at := time.Date(2024, 3, 1, 12, 30, 15, 0, time.UTC)
+
+	val, err := TextTime{at}.Value()
+	require.NoError(t, err)
+	require.Equal(t, "2024-03-01 12:30:15+00:00", val)
+	val, err = UnixTime{at}.Value()
+	require.NoError(t, err)
+	require.Equal(t, at.Unix(), val)
+	val, err = JulianTime{at}.Value()
+	require.NoError(t, err)
+	require.InDelta(t, 2460371.0210069446, val, 1e-9)
+
+	for _, src := range []any{
+		"2024-03-01 12:30:15+00:00",
+		[]byte("2024-03-01T12:30:15Z"),
+		"2024-03-01 12:30:15",
+		at.Unix(),
+		"1709296215",
+		2460371.0210069446,
+		at,
+	} {
+		var scan TextTime
+		require.NoError(t, scan.Scan(src), "%v", src)
+		require.True(t, at.Equal(scan.Time), "%v | %v", src, scan.Time)
+	}
+
+	var day UnixTime
+	require.NoError(t, day.Scan("2024-03-01"))
+	require.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), day.Time)
+	var clock JulianTime
+	require.NoError(t, clock.Scan("12:30"))
+	require.Equal(t, 12, clock.Hour())
+
+	require.Error(t, day.Scan("yesterday"))
+	require.Error(t, day.Scan(true))
+}
@@ -0,0 +1,23 @@
+package db
+
+import (
+	"fmt"
+	"slices"
+)
+
+// CheckTpl checks the value of a #name# tpl before it is formatted into the sql.
+// The value must be one of allowed, or letters, digits and _ only (e.g. a table suffix) when allowed is empty.
+func CheckTpl(name, value string, allowed ...string) error {
+	if len(allowed) > 0 {
+		if slices.Contains(allowed, value) == false {
+			return fmt.Errorf("tpl %s | %q is not an allowed value", name, value)
+		}
+		return nil
+	}
+	for i := 0; i < len(value); i++ {
+		if IsIdentChar(value[i]) == false {
+			return fmt.Errorf("tpl %s | %q is not an identifier", name, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckTpl(t *testing.T) {
+	require.NoError(t, CheckTpl("shard", "2024_01"))
+	require.NoError(t, CheckTpl("shard", ""))
+	require.Error(t, CheckTpl("shard", "x; DROP TABLE users"))
+	require.Error(t, CheckTpl("shard", "a b"))
+
+	require.NoError(t, CheckTpl("order", "created_at DESC", "id", "created_at DESC"))
+	require.Error(t, CheckTpl("order", "name", "id", "created_at DESC"))
+}
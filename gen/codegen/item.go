@@ -79,6 +79,45 @@ func (t *Struct) Code(w *Writer) {
 	}
 }
 
+//--------------------------------------------------------------------------------------------------------------//
+// type
+
+type Type struct {
+	Name    string
+	Type    string // underlying type
+	Consts  *Consts
+	Methods []*Function
+}
+
+func (t *Type) AddConst(item *Const) {
+	if t.Consts == nil {
+		t.Consts = &Consts{}
+	}
+	t.Consts.Add(item)
+}
+
+func (t *Type) AddFunction(item *Function) {
+	if t.Methods == nil {
+		t.Methods = make([]*Function, 0, 10)
+	}
+	t.Methods = append(t.Methods, item)
+}
+
+func (t *Type) Code(w *Writer) {
+	w.W("type %s %s\n\n", t.Name, t.Type)
+
+	// const
+	if t.Consts != nil {
+		t.Consts.Code(w)
+		w.W("\n")
+	}
+
+	// func
+	for _, method := range t.Methods {
+		method.Code(w)
+	}
+}
+
 //--------------------------------------------------------------------------------------------------------------//
 // function
 
@@ -0,0 +1,96 @@
+package gen
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gosuda/ornn/parser"
+)
+
+// Diagnostic is an error of a query in the config, located in its sql when the parser knows where.
+type Diagnostic struct {
+	File    string `json:"file,omitempty"`
+	Query   string `json:"query"`            // <group>.<query name>
+	Line    int    `json:"line,omitempty"`   // 1-based, 0 if unknown
+	Column  int    `json:"column,omitempty"` // 1-based in characters, 0 if unknown
+	Message string `json:"message"`
+	Snippet string `json:"snippet,omitempty"` // sql line with a caret under the column
+}
+
+func newDiagnostic(file, queryName, sql string, err error) *Diagnostic {
+	diag := &Diagnostic{
+		File:    file,
+		Query:   queryName,
+		Message: strings.TrimPrefix(err.Error(), "parser error | "),
+	}
+
+	offset := parser.ErrorOffset(sql, err)
+	if offset < 0 {
+		return diag
+	}
+	lineStart := strings.LastIndexByte(sql[:offset], '\n') + 1
+	lineEnd := strings.IndexByte(sql[offset:], '\n')
+	if lineEnd == -1 {
+		lineEnd = len(sql)
+	} else {
+		lineEnd += offset
+	}
+	line := strings.TrimRight(sql[lineStart:lineEnd], "\r")
+
+	diag.Line = strings.Count(sql[:offset], "\n") + 1
+	diag.Column = utf8.RuneCountInString(sql[lineStart:offset]) + 1
+
+	// tab 은 그대로 두어 caret 이 같은 위치에 오도록 맞춤
+	pad := []rune(sql[lineStart:offset])
+	for i, r := range pad {
+		if r != '\t' {
+			pad[i] = ' '
+		}
+	}
+	diag.Snippet = line + "\n" + string(pad) + "^"
+	return diag
+}
+
+// String formats the diagnostic like a compiler error, e.g.
+//
+//	config.json: users.selectByName:1:34: unknown column nmae in table users, did you mean name?
+//	    SELECT * FROM users WHERE nmae = ?
+//	                              ^
+func (t *Diagnostic) String() string {
+	var b strings.Builder
+	if t.File != "" {
+		b.WriteString(t.File + ": ")
+	}
+	b.WriteString(t.Query)
+	if t.Line > 0 {
+		fmt.Fprintf(&b, ":%d:%d", t.Line, t.Column)
+	}
+	b.WriteString(": " + t.Message + "\n")
+	if t.Snippet != "" {
+		for _, line := range strings.Split(t.Snippet, "\n") {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// Diagnostics are the query errors of a generation, returned by Gen as an error.
+type Diagnostics []*Diagnostic
+
+func (t Diagnostics) Error() string {
+	var b strings.Builder
+	for _, diag := range t {
+		b.WriteString(diag.String())
+	}
+	return b.String()
+}
+
+// JSON returns the diagnostics as a json array, for editor integration.
+func (t Diagnostics) JSON() ([]byte, error) {
+	if t == nil {
+		t = Diagnostics{}
+	}
+	return json.MarshalIndent(t, "", "\t")
+}
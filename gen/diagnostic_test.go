@@ -0,0 +1,31 @@
+package gen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gosuda/ornn/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiagnostic(t *testing.T) {
+	sql := "SELECT *\n\tFROM users\n\tWHERE nmae = ?"
+	err := &parser.Error{Msg: "parser error | unknown column nmae in table users, did you mean name?", Offset: -1, Ident: "nmae"}
+
+	diag := newDiagnostic("config.json", "users.selectByName", sql, err)
+	require.Equal(t, 3, diag.Line)
+	require.Equal(t, 8, diag.Column)
+	require.Equal(t, "\tWHERE nmae = ?\n\t      ^", diag.Snippet)
+	require.Equal(t, "config.json: users.selectByName:3:8: unknown column nmae in table users, did you mean name?\n"+
+		"    \tWHERE nmae = ?\n"+
+		"    \t      ^\n", diag.String())
+
+	// 위치를 모르는 에러
+	diag = newDiagnostic("", "users.update", sql, errors.New("placeholder count mismatch"))
+	require.Zero(t, diag.Line)
+	require.Equal(t, "users.update: placeholder count mismatch\n", diag.String())
+
+	data, err2 := Diagnostics{diag}.JSON()
+	require.NoError(t, err2)
+	require.JSONEq(t, `[{"query": "users.update", "message": "placeholder count mismatch"}]`, string(data))
+}
@@ -1,11 +1,8 @@
 package gen
 
 import (
-	"fmt"
-
 	"github.com/gosuda/ornn/config"
 	"github.com/gosuda/ornn/parser"
-	"github.com/rs/zerolog/log"
 )
 
 type Gen struct {
@@ -23,21 +20,8 @@ func (t *Gen) Gen(conf *config.Config, psr parser.Parser) (code string, err erro
 	}
 
 	// check query error
-	for tableName, def := range conf.Queries.Class {
-		for _, query := range def {
-			if query.ErrParser != "" {
-				log.Error().Str("table name", tableName).Str("query name", query.Name).Str("err", query.ErrParser).Msg("parser err")
-				err = fmt.Errorf("query error")
-			}
-			if query.ErrQuery != "" {
-				log.Error().Str("table name", tableName).Str("query name", query.Name).Str("err", query.ErrParser).Msg("parser err")
-				err = fmt.Errorf("query error")
-			}
-		}
-	}
-
-	if err != nil {
-		return "", err
+	if len(t.data.diagnostics) > 0 {
+		return "", t.data.diagnostics
 	}
 
 	// gen code
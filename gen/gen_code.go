@@ -3,9 +3,10 @@ package gen
 import (
 	"fmt"
 	"log"
-
+	"strconv"
 	"strings"
 
+	"github.com/gosuda/ornn/atlas"
 	"github.com/gosuda/ornn/config"
 	"github.com/gosuda/ornn/gen/codegen"
 	"github.com/gosuda/ornn/gen/template"
@@ -28,6 +29,17 @@ func (t *GenCode) code(config *config.Config, genQueries *GenQueries) (genCode s
 			Alias: imp.Alias,
 		})
 	}
+	// imports of the go types the parsers may generate, unused ones are removed on format
+	for _, path := range t.typeImports() {
+		t.codeGen.AddImport(&codegen.Import{
+			Path: path,
+		})
+	}
+
+	// enum types
+	for _, enum := range parser.Enums(t.conf.Schema.Schema) {
+		t.codeGen.AddItem(t.genEnum(enum))
+	}
 
 	// root struct
 	rootStruct := &codegen.Struct{
@@ -65,6 +77,10 @@ func (t *GenCode) code(config *config.Config, genQueries *GenQueries) (genCode s
 			genFunc := t.genFunc(genClass.Name, funcName, query)
 			t.codeGen.AddItem(genFunc)
 		}
+
+		if columns, ok := genQueries.copyFrom[groupName]; ok == true {
+			t.codeGen.AddItem(t.genCopyFrom(genClass.Name, groupName, columns))
+		}
 	}
 
 	// 소스 출력
@@ -72,6 +88,87 @@ func (t *GenCode) code(config *config.Config, genQueries *GenQueries) (genCode s
 	return genCode, nil
 }
 
+// typeImports returns the import paths of the type overrides and of the built-in go types of the db
+// which are not imported by the config yet.
+func (t *GenCode) typeImports() (paths []string) {
+	seen := make(map[string]bool)
+	for _, imp := range t.conf.Global.Import {
+		seen[imp.Path] = true
+	}
+	add := func(path string) {
+		if path != "" && seen[path] == false {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	for _, override := range t.conf.Global.TypeOverrides {
+		add(override.Import)
+	}
+	switch t.conf.Schema.DbType {
+	case atlas.DbTypePostgre, atlas.DbTypeCockroachDB:
+		add("github.com/lib/pq")        // pq.Array
+		add("github.com/lib/pq/hstore") // hstore.Hstore
+	}
+	return paths
+}
+
+// genEnum generates a string type for the enum with a constant per value, Valid and the sql Scanner / Valuer.
+func (t *GenCode) genEnum(enum *parser.Enum) (genEnum *codegen.Type) {
+	genEnum = t.genEnumType(enum)
+
+	funcScan := &codegen.Function{
+		StructName: "t",
+		StructType: "*" + enum.GoType,
+		FuncName:   "Scan",
+		InlineCode: "return ScanEnum(t, src)",
+	}
+	funcScan.AddArg(&codegen.Var{Name: "src", Type: "any"})
+	funcScan.AddRet(&codegen.Var{Type: "error"})
+	genEnum.AddFunction(funcScan)
+
+	funcValue := &codegen.Function{
+		StructName: "t",
+		StructType: enum.GoType,
+		FuncName:   "Value",
+		InlineCode: "return EnumValue(t)",
+	}
+	funcValue.AddRet(&codegen.Var{Type: "driver.Value"})
+	funcValue.AddRet(&codegen.Var{Type: "error"})
+	genEnum.AddFunction(funcValue)
+
+	return genEnum
+}
+
+// genEnumType generates a string type for the enum with a constant per value and Valid.
+func (t *GenCode) genEnumType(enum *parser.Enum) (genEnum *codegen.Type) {
+	genEnum = &codegen.Type{
+		Name: enum.GoType,
+		Type: "string",
+	}
+
+	consts := make([]string, 0, len(enum.Values))
+	for _, value := range enum.Values {
+		genEnum.AddConst(&codegen.Const{
+			Name:  enum.ConstName(value),
+			Type:  enum.GoType,
+			Value: strconv.Quote(value),
+		})
+		consts = append(consts, enum.ConstName(value))
+	}
+
+	funcValid := &codegen.Function{
+		StructName: "t",
+		StructType: enum.GoType,
+		FuncName:   "Valid",
+		InlineCode: fmt.Sprintf("switch t {\ncase %s:\n\treturn true\n}\nreturn false", strings.Join(consts, ", ")),
+	}
+	funcValid.AddRet(&codegen.Var{Type: "bool"})
+	genEnum.AddFunction(funcValid)
+
+	return genEnum
+}
+
 func (t *GenCode) genClass(name string) (genGroup *codegen.Struct) {
 	genGroup = &codegen.Struct{
 		Name: util.ConvFirstToUpper(name),
@@ -112,21 +209,83 @@ func (t *GenCode) genFunc(groupName, queryName string, query *parser.ParsedQuery
 		FuncName:   util.ConvFirstToUpper(queryName),
 	}
 
+	if len(query.Stmts) > 0 {
+		t.genQueryMulti(groupName, queryName, funcQuery, query)
+		return funcQuery
+	}
+
 	switch query.QueryType {
 	case parser.QueryTypeSelect:
 		t.genQuerySelect(groupName, funcQuery, query)
-	case parser.QueryTypeInsert:
-		t.genQueryInsert(funcQuery, query)
-	case parser.QueryTypeUpdate:
-		t.genQueryUpdate(funcQuery, query)
-	case parser.QueryTypeDelete:
-		t.genQueryDelete(funcQuery, query)
+	case parser.QueryTypeInsert, parser.QueryTypeUpdate, parser.QueryTypeDelete:
+		if len(query.Ret) > 0 {
+			t.genQueryReturning(groupName, funcQuery, query)
+			break
+		}
+		switch query.QueryType {
+		case parser.QueryTypeInsert:
+			t.genQueryInsert(funcQuery, query)
+		case parser.QueryTypeUpdate:
+			t.genQueryUpdate(funcQuery, query)
+		case parser.QueryTypeDelete:
+			t.genQueryDelete(funcQuery, query)
+		}
 	default:
 		log.Fatalf("need more programming | invalid query type | query type : %v", query.QueryType)
 	}
+
+	t.genQuery_checkTpls(funcQuery, query)
 	return funcQuery
 }
 
+// genQuery_checkTpls prepends the checks of the tpl values, before they are formatted into the sql
+func (t *GenCode) genQuery_checkTpls(funcQuery *codegen.Function, query *parser.ParsedQuery) {
+	checks := ""
+	for _, tpl := range query.Tpl {
+		checks += template.CheckTpl(tpl.Name, "tpl_"+tpl.Name, tpl.Allowed, t.genQuery_zero(funcQuery))
+	}
+	if checks != "" {
+		funcQuery.InlineCode = checks + "\n" + funcQuery.InlineCode
+	}
+}
+
+// genQueryMulti generates a multi-statement query as one function running the statements in order in a transaction.
+// Every statement is generated as an unexported function, and the result of the last one is returned.
+func (t *GenCode) genQueryMulti(groupName, queryName string, funcQuery *codegen.Function, query *parser.ParsedQuery) {
+	calls := make([]string, 0, len(query.Stmts))
+	var stmtFunc *codegen.Function
+	for i, stmt := range query.Stmts {
+		stmtFunc = t.genFunc(groupName, fmt.Sprintf("%s_%d", queryName, i+1), stmt)
+		stmtFunc.FuncName = strings.ToLower(stmtFunc.FuncName[:1]) + stmtFunc.FuncName[1:]
+		t.codeGen.AddItem(stmtFunc)
+
+		args := make([]string, 0)
+		if stmtFunc.Args != nil {
+			for _, arg := range stmtFunc.Args.Items {
+				args = append(args, arg.Name)
+			}
+		}
+		calls = append(calls, fmt.Sprintf("%s(%s)", stmtFunc.FuncName, strings.Join(args, ", ")))
+	}
+
+	// args
+	t.genQuery_tpls(funcQuery, query)
+	t.genQuery_args(funcQuery, query)
+
+	// rets - same as the last statement
+	rets := make([]string, 0, len(stmtFunc.Rets.Items))
+	for _, ret := range stmtFunc.Rets.Items {
+		funcQuery.AddRet(&codegen.Var{
+			Name: ret.Name,
+			Type: ret.Type,
+		})
+		rets = append(rets, ret.Name)
+	}
+
+	// body
+	funcQuery.InlineCode = template.Multi(calls, rets, groupName, "t", "job")
+}
+
 func (t *GenCode) genQuerySelect(groupName string, funcQuery *codegen.Function, query *parser.ParsedQuery) {
 	// struct for select
 	structName := t.genQuery_struct_select(groupName, funcQuery, query)
@@ -134,26 +293,156 @@ func (t *GenCode) genQuerySelect(groupName string, funcQuery *codegen.Function,
 	// args
 	tpls := t.genQuery_tpls(funcQuery, query)
 	args := t.genQuery_args(funcQuery, query)
+	t.genQuery_args_orderBy(funcQuery, query, structName)
 
 	// rets
 	retItemName, retItemType := t.genQuery_ret_select(funcQuery, structName, query.SelectSingle)
 	t.genQuery_ret_error(funcQuery)
 
 	// body
-	funcQuery.InlineCode = template.Select(args, tpls, query.Query, query.SelectSingle, "t", "job", structName, retItemName, retItemType)
+	funcQuery.InlineCode = template.Select(args, tpls, query.Query, len(query.OrderBy) > 0, false, t.hasOptionalArg(query), t.hasSliceArg(query), query.SelectSingle, "t", "job", structName, retItemName, retItemType, t.genQuery_scanDests(query))
+
+	if query.Page != nil {
+		t.codeGen.AddItem(t.genQueryPage(funcQuery, query, structName))
+	}
+}
+
+// genQueryPage generates the keyset paginated select of the paginate option, <Func>Page returning
+// a page of rows after the cursor and the cursor of the next page, empty on the last page. see db.Keyset
+func (t *GenCode) genQueryPage(selectFunc *codegen.Function, query *parser.ParsedQuery, structName string) (funcQuery *codegen.Function) {
+	funcQuery = &codegen.Function{
+		StructName: selectFunc.StructName,
+		StructType: selectFunc.StructType,
+		FuncName:   selectFunc.FuncName + "Page",
+	}
+
+	// args
+	tpls := t.genQuery_tpls(funcQuery, query)
+	args := t.genQuery_args(funcQuery, query)
+	funcQuery.AddArg(&codegen.Var{Name: "cursor", Type: "string"})
+	funcQuery.AddArg(&codegen.Var{Name: "limit", Type: "int"})
+
+	// rets
+	funcQuery.AddRet(&codegen.Var{Name: "rows", Type: "[]*" + structName})
+	funcQuery.AddRet(&codegen.Var{Name: "next", Type: "string"})
+	t.genQuery_ret_error(funcQuery)
+
+	// body
+	columns := make([]string, len(query.Page.Columns))
+	for i, column := range query.Page.Columns {
+		columns[i] = strconv.Quote(column)
+	}
+	keyset := fmt.Sprintf("Keyset{Columns: []string{%s}, Desc: %t, Numbered: %t}", strings.Join(columns, ", "), query.Page.Desc, query.Page.Numbered)
+	fields := make([]string, len(query.Page.Fields))
+	for i, field := range query.Page.Fields {
+		fields[i] = util.ConvFirstToUpper(field)
+	}
+	funcQuery.InlineCode = template.Page(args, tpls, query.Query, t.hasOptionalArg(query), t.hasSliceArg(query), keyset, fields, "t", "job", structName, "rows", "[]*"+structName, t.genQuery_scanDests(query))
+	t.genQuery_checkTpls(funcQuery, query)
+	return funcQuery
+}
+
+// genQueryReturning generates insert/update/delete with a RETURNING clause, returning the typed rows instead of lastInsertId/rowAffected
+func (t *GenCode) genQueryReturning(groupName string, funcQuery *codegen.Function, query *parser.ParsedQuery) {
+	// struct for returning
+	structName := t.genQuery_struct_select(groupName, funcQuery, query)
+
+	// args
+	tpls := t.genQuery_tpls(funcQuery, query)
+	args := t.genQuery_args(funcQuery, query)
+
+	// rets - an insert of one VALUES row returns the inserted row, anything else every returned row
+	// (ON CONFLICT DO NOTHING may insert none)
+	single := query.QueryType == parser.QueryTypeInsert && query.InsertOne == true
+	retItemName, retItemType := t.genQuery_ret_select(funcQuery, structName, single)
+	t.genQuery_ret_error(funcQuery)
+
+	// body
+	if single == true {
+		funcQuery.InlineCode = template.Returning(args, tpls, query.Query, t.hasSliceArg(query), "t", "job", structName, t.genQuery_scanDests(query))
+	} else {
+		funcQuery.InlineCode = template.Select(args, tpls, query.Query, false, query.UpdateNullIgnore, t.hasOptionalArg(query), t.hasSliceArg(query), false, "t", "job", structName, retItemName, retItemType, t.genQuery_scanDests(query))
+	}
 }
 
 func (t *GenCode) genQueryInsert(funcQuery *codegen.Function, query *parser.ParsedQuery) {
+	if query.InsertMulti == true {
+		t.genQueryBulkInsert(funcQuery, query)
+		return
+	}
+
 	// args
 	args := t.genQuery_args(funcQuery, query)
 	tpls := t.genQuery_tpls(funcQuery, query)
 
+	// postgres has no last insert id (lib/pq rejects LastInsertId), the affected rows are returned instead.
+	// RETURNING is the way to get the inserted row, see genQueryReturning
+	if t.conf != nil && (t.conf.Schema.DbType == atlas.DbTypePostgre || t.conf.Schema.DbType == atlas.DbTypeCockroachDB) {
+		t.genQuery_ret_rowAffected(funcQuery)
+		t.genQuery_ret_error(funcQuery)
+		funcQuery.InlineCode = template.Update(args, tpls, query.Query, false, false, t.hasSliceArg(query), "t", "job")
+		return
+	}
+
 	// rets
 	t.genQuery_ret_lastInsertId(funcQuery)
 	t.genQuery_ret_error(funcQuery)
 
 	// body
-	funcQuery.InlineCode = template.Insert(args, tpls, query.Query, query.InsertMulti, "t", "job")
+	funcQuery.InlineCode = template.Insert(args, tpls, query.Query, t.hasSliceArg(query), "t", "job")
+}
+
+// genQueryBulkInsert generates an insert taking a slice of row structs, executed as chunked multi-row inserts
+func (t *GenCode) genQueryBulkInsert(funcQuery *codegen.Function, query *parser.ParsedQuery) {
+	// args
+	tpls := t.genQuery_tpls(funcQuery, query)
+	rowName, rowArgs := t.genQuery_args_bulk(funcQuery, query)
+
+	// rets
+	t.genQuery_ret_rowAffected(funcQuery)
+	t.genQuery_ret_error(funcQuery)
+
+	// body
+	funcQuery.InlineCode = template.BulkInsert(rowName, rowArgs, tpls, query.Query, t.bulkMaxArgs(), "t", "job")
+}
+
+// genCopyFrom generates CopyFrom<Table>, loading a slice of table rows with postgres COPY FROM
+func (t *GenCode) genCopyFrom(groupName, tableName string, columns []*parser.ParsedQueryField) (funcQuery *codegen.Function) {
+	funcQuery = &codegen.Function{
+		StructName: "t",
+		StructType: "*" + groupName,
+		FuncName:   "CopyFrom" + util.ConvFirstToUpper(tableName),
+	}
+
+	// args
+	rowStruct := &codegen.Struct{
+		Name: fmt.Sprintf("%s_%s", groupName, strings.ToLower(funcQuery.FuncName)),
+	}
+	names := make([]string, 0, len(columns))
+	rowArgs := make([]string, 0, len(columns))
+	for _, col := range columns {
+		field := util.ConvFirstToUpper(col.Name)
+		rowStruct.AddField(&codegen.Var{
+			Name: field,
+			Type: col.GoType,
+		})
+		names = append(names, col.Name)
+		rowArgs = append(rowArgs, t.bindExpr(col, "rows[i]."+field))
+	}
+	t.codeGen.AddItem(rowStruct)
+
+	funcQuery.AddArg(&codegen.Var{
+		Name: "rows",
+		Type: "[]" + rowStruct.Name,
+	})
+
+	// rets
+	t.genQuery_ret_rowAffected(funcQuery)
+	t.genQuery_ret_error(funcQuery)
+
+	// body
+	funcQuery.InlineCode = template.CopyFrom(tableName, names, rowArgs, "t", "job")
+	return funcQuery
 }
 
 func (t *GenCode) genQueryUpdate(funcQuery *codegen.Function, query *parser.ParsedQuery) {
@@ -166,7 +455,7 @@ func (t *GenCode) genQueryUpdate(funcQuery *codegen.Function, query *parser.Pars
 	t.genQuery_ret_error(funcQuery)
 
 	// body
-	funcQuery.InlineCode = template.Update(args, tpls, query.Query, "t", "job")
+	funcQuery.InlineCode = template.Update(args, tpls, query.Query, query.UpdateNullIgnore, t.hasOptionalArg(query), t.hasSliceArg(query), "t", "job")
 }
 
 func (t *GenCode) genQueryDelete(funcQuery *codegen.Function, query *parser.ParsedQuery) {
@@ -179,7 +468,7 @@ func (t *GenCode) genQueryDelete(funcQuery *codegen.Function, query *parser.Pars
 	t.genQuery_ret_error(funcQuery)
 
 	// body
-	funcQuery.InlineCode = template.Delete(args, query.Query, tpls, "t", "job")
+	funcQuery.InlineCode = template.Delete(args, query.Query, t.hasOptionalArg(query), t.hasSliceArg(query), tpls, "t", "job")
 }
 
 func (t *GenCode) genQuery_tpls(funcQuery *codegen.Function, query *parser.ParsedQuery) (tpls []string) {
@@ -198,20 +487,118 @@ func (t *GenCode) genQuery_tpls(funcQuery *codegen.Function, query *parser.Parse
 func (t *GenCode) genQuery_args(funcQuery *codegen.Function, query *parser.ParsedQuery) (args []string) {
 	args = make([]string, 0, len(query.Arg))
 
+	// a named arg used at several placeholders is declared once and bound at each of them,
+	// the parsers give the other args unique names, see parser.ParsedQuery.UniqueArgNames
+	declared := make(map[string]bool, len(query.Arg))
 	for _, a := range query.Arg {
+		name := a.Name
+		if a.IsSlice == true {
+			// expanded to one placeholder per item at runtime
+			args = append(args, fmt.Sprintf("NewSliceArg(%s)", name))
+		} else if a.IsOptional == true {
+			// dropped with its WHERE predicate at runtime when nil
+			args = append(args, fmt.Sprintf("NewOptionalArg(%s)", name))
+		} else {
+			args = append(args, t.bindExpr(a, name))
+		}
+		if declared[name] == true {
+			continue
+		}
+		declared[name] = true
+
 		arg := &codegen.Var{
-			Name: a.Name,
+			Name: name,
 			Type: a.GoType,
 		}
-		if query.InsertMulti == true {
+		if a.IsSlice == true {
 			arg.Type = "[]" + arg.Type
 		}
 		funcQuery.AddArg(arg)
-		args = append(args, arg.Name)
 	}
 	return args
 }
 
+// genQuery_args_orderBy declares the OrderBy enum of the sortable columns, and the orderBy and direction args
+func (t *GenCode) genQuery_args_orderBy(funcQuery *codegen.Function, query *parser.ParsedQuery, structName string) {
+	if len(query.OrderBy) == 0 {
+		return
+	}
+	enum := &parser.Enum{
+		GoType: structName + "OrderBy",
+		Values: query.OrderBy,
+	}
+	t.codeGen.AddItem(t.genEnumType(enum))
+
+	funcQuery.AddArg(&codegen.Var{
+		Name: "orderBy",
+		Type: enum.GoType,
+	})
+	funcQuery.AddArg(&codegen.Var{
+		Name: "direction",
+		Type: "Direction",
+	})
+}
+
+// genQuery_args_bulk declares the row struct of a bulk insert and a rows arg, and returns the row fields bound to each placeholder
+func (t *GenCode) genQuery_args_bulk(funcQuery *codegen.Function, query *parser.ParsedQuery) (rowName string, rowArgs []string) {
+	rowStruct := &codegen.Struct{
+		Name: fmt.Sprintf("%s_%s", strings.TrimPrefix(funcQuery.StructType, "*"), strings.ToLower(funcQuery.FuncName)),
+	}
+	rowArgs = make([]string, 0, len(query.Arg))
+
+	declared := make(map[string]bool, len(query.Arg))
+	for _, a := range query.Arg {
+		field := util.ConvFirstToUpper(a.Name)
+		rowArgs = append(rowArgs, t.bindExpr(a, "row."+field))
+		if declared[field] == true {
+			continue
+		}
+		declared[field] = true
+		rowStruct.AddField(&codegen.Var{
+			Name: field,
+			Type: a.GoType,
+		})
+	}
+	t.codeGen.AddItem(rowStruct)
+
+	funcQuery.AddArg(&codegen.Var{
+		Name: "rows",
+		Type: "[]" + rowStruct.Name,
+	})
+	return rowStruct.Name, rowArgs
+}
+
+// bulkMaxArgs returns the bind arg limit of the target db, see db.BulkInsert
+func (t *GenCode) bulkMaxArgs() string {
+	switch t.conf.Schema.DbType {
+	case atlas.DbTypePostgre, atlas.DbTypeCockroachDB:
+		return "BulkMaxArgsPostgres"
+	case atlas.DbTypeSQLite:
+		return "BulkMaxArgsSQLite"
+	default:
+		return "BulkMaxArgsMySQL"
+	}
+}
+
+// hasOptionalArg reports whether the query has optional args, see db.OptionalWhere
+func (t *GenCode) hasOptionalArg(query *parser.ParsedQuery) bool {
+	for _, a := range query.Arg {
+		if a.IsOptional == true {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *GenCode) hasSliceArg(query *parser.ParsedQuery) bool {
+	for _, a := range query.Arg {
+		if a.IsSlice == true {
+			return true
+		}
+	}
+	return false
+}
+
 func (t *GenCode) genQuery_ret_error(funcQuery *codegen.Function) {
 	funcQuery.AddRet(&codegen.Var{
 		Name: "err",
@@ -219,6 +606,27 @@ func (t *GenCode) genQuery_ret_error(funcQuery *codegen.Function) {
 	})
 }
 
+// genQuery_zero returns the zero values of the rets before err, returned with the error by the error exits of the body
+func (t *GenCode) genQuery_zero(funcQuery *codegen.Function) string {
+	if funcQuery.Rets == nil {
+		return ""
+	}
+	zeros := make([]string, 0, len(funcQuery.Rets.Items))
+	for _, ret := range funcQuery.Rets.Items[:len(funcQuery.Rets.Items)-1] {
+		switch {
+		case strings.HasPrefix(ret.Type, "[]"), strings.HasPrefix(ret.Type, "*"), strings.HasPrefix(ret.Type, "map["):
+			zeros = append(zeros, "nil")
+		case ret.Type == "string":
+			zeros = append(zeros, `""`)
+		case ret.Type == "bool":
+			zeros = append(zeros, "false")
+		default:
+			zeros = append(zeros, "0")
+		}
+	}
+	return strings.Join(zeros, ", ")
+}
+
 func (t *GenCode) genQuery_struct_select(groupName string, funcQuery *codegen.Function, query *parser.ParsedQuery) (retStructName string) {
 	retStruct := &codegen.Struct{
 		Name: fmt.Sprintf("%s_%s", util.ConvFirstToUpper(groupName), strings.ToLower(funcQuery.FuncName)),
@@ -233,6 +641,35 @@ func (t *GenCode) genQuery_struct_select(groupName string, funcQuery *codegen.Fu
 	return retStruct.Name
 }
 
+// genQuery_scanDests returns the scan destinations of the returned columns into the struct fields, in scan order
+func (t *GenCode) genQuery_scanDests(query *parser.ParsedQuery) (dests []string) {
+	dests = make([]string, 0, len(query.Ret))
+	for _, r := range query.Ret {
+		dest := "&scan." + util.ConvFirstToUpper(r.Name)
+		if t.pgArray(r) == true {
+			dest = fmt.Sprintf("pq.Array(%s)", dest)
+		}
+		dests = append(dests, dest)
+	}
+	return dests
+}
+
+// bindExpr returns the expression binding the value of the field to a placeholder
+func (t *GenCode) bindExpr(field *parser.ParsedQueryField, value string) string {
+	if t.pgArray(field) == true {
+		return fmt.Sprintf("pq.Array(%s)", value)
+	}
+	return value
+}
+
+// pgArray reports whether the field is a postgres array, which lib/pq binds and scans through pq.Array
+func (t *GenCode) pgArray(field *parser.ParsedQueryField) bool {
+	if t.conf == nil || (t.conf.Schema.DbType != atlas.DbTypePostgre && t.conf.Schema.DbType != atlas.DbTypeCockroachDB) {
+		return false
+	}
+	return field.IsSlice == false && strings.HasPrefix(field.GoType, "[]") && field.GoType != "[]byte"
+}
+
 func (t *GenCode) genQuery_ret_select(funcQuery *codegen.Function, retStructName string, selectSingle bool) (retItemName, retItemType string) {
 	retItem := &codegen.Var{
 		Name: strings.ToLower(funcQuery.FuncName),
@@ -0,0 +1,324 @@
+package gen
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/gosuda/ornn/atlas"
+	"github.com/gosuda/ornn/config"
+	"github.com/gosuda/ornn/gen/codegen"
+	"github.com/gosuda/ornn/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenQueryArgsNamedOnce(t *testing.T) {
+	pq := &parser.ParsedQuery{}
+	pq.Init("SELECT * FROM users WHERE name = ? OR email = ?")
+	pq.Arg = append(pq.Arg, parser.NewField("term", "string"), parser.NewField("term", "string"))
+	for _, arg := range pq.Arg {
+		arg.IsNamed = true
+	}
+
+	genCode := &GenCode{}
+	funcQuery := &codegen.Function{FuncName: "Search"}
+	args := genCode.genQuery_args(funcQuery, pq)
+
+	require.Equal(t, []string{"term", "term"}, args)
+	require.Len(t, funcQuery.Args.Items, 1)
+	require.Equal(t, "term", funcQuery.Args.Items[0].Name)
+}
+
+func TestGenQueryArgsSlice(t *testing.T) {
+	pq := &parser.ParsedQuery{}
+	pq.Init("SELECT * FROM users WHERE id IN (?) AND age > ?")
+	pq.Arg = append(pq.Arg, parser.NewField("ids", "uint64"), parser.NewField("where_age", "int32"))
+	pq.Arg[0].IsSlice = true
+
+	genCode := &GenCode{}
+	funcQuery := &codegen.Function{FuncName: "SelectByIds"}
+	args := genCode.genQuery_args(funcQuery, pq)
+
+	require.Equal(t, []string{"NewSliceArg(ids)", "where_age"}, args)
+	require.Equal(t, "[]uint64", funcQuery.Args.Items[0].Type)
+	require.Equal(t, "int32", funcQuery.Args.Items[1].Type)
+	require.True(t, genCode.hasSliceArg(pq))
+}
+
+func TestGenFuncReturning(t *testing.T) {
+	pq := &parser.ParsedQuery{}
+	pq.Init("INSERT INTO users (name) VALUES ($1) RETURNING id, name")
+	pq.QueryType = parser.QueryTypeInsert
+	pq.InsertOne = true
+	pq.Arg = append(pq.Arg, parser.NewField("val_name", "string"))
+	pq.Ret = append(pq.Ret, parser.NewField("id", "int64"), parser.NewField("name", "string"))
+
+	genCode := &GenCode{codeGen: &codegen.CodeGen{}}
+	funcQuery := genCode.genFunc("Users", "insert", pq)
+	require.Equal(t, "*Users_insert", funcQuery.Rets.Items[0].Type)
+	require.Contains(t, funcQuery.InlineCode, ".QueryRow(")
+	require.Contains(t, funcQuery.InlineCode, ".Scan(&scan.Id, &scan.Name)")
+
+	// ON CONFLICT DO NOTHING may insert no row
+	pq.Init("INSERT INTO users (name) VALUES ($1) ON CONFLICT DO NOTHING RETURNING id, name")
+	pq.InsertOne = false
+	pq.Arg = append(pq.Arg, parser.NewField("val_name", "string"))
+	pq.Ret = append(pq.Ret, parser.NewField("id", "int64"), parser.NewField("name", "string"))
+	funcQuery = genCode.genFunc("Users", "insertIgnore", pq)
+	require.Equal(t, "[]*Users_insertignore", funcQuery.Rets.Items[0].Type)
+	require.Contains(t, funcQuery.InlineCode, ".Query(")
+
+	// update returns every affected row
+	pq.QueryType = parser.QueryTypeUpdate
+	funcQuery = genCode.genFunc("Users", "update", pq)
+	require.Equal(t, "[]*Users_update", funcQuery.Rets.Items[0].Type)
+	require.Contains(t, funcQuery.InlineCode, ".Query(")
+}
+
+func TestGenFuncInsertPostgres(t *testing.T) {
+	pq := &parser.ParsedQuery{}
+	pq.Init("INSERT INTO users VALUES ($1, $2)")
+	pq.QueryType = parser.QueryTypeInsert
+	pq.Arg = append(pq.Arg, parser.NewField("val_id", "int64"), parser.NewField("val_name", "string"))
+
+	conf := &config.Config{}
+	conf.Schema.Init(atlas.DbTypePostgre, &schema.Schema{})
+	genCode := &GenCode{conf: conf, codeGen: &codegen.CodeGen{}}
+	funcQuery := genCode.genFunc("Users", "insert", pq)
+	require.Equal(t, "rowAffected", funcQuery.Rets.Items[0].Name)
+	require.Contains(t, funcQuery.InlineCode, "exec.RowsAffected()")
+	require.NotContains(t, funcQuery.InlineCode, "LastInsertId")
+
+	// lastInsertId where the driver has it
+	conf.Schema.Init(atlas.DbTypeMySQL, &schema.Schema{})
+	funcQuery = genCode.genFunc("Users", "insert", pq)
+	require.Equal(t, "lastInsertId", funcQuery.Rets.Items[0].Name)
+}
+
+func TestGenFuncBulkInsert(t *testing.T) {
+	pq := &parser.ParsedQuery{}
+	pq.Init("INSERT INTO users (name, email) VALUES ($1, lower($1))")
+	pq.QueryType = parser.QueryTypeInsert
+	pq.InsertMulti = true
+	pq.Arg = append(pq.Arg, parser.NewField("name", "string"))
+
+	conf := &config.Config{}
+	conf.Schema.DbType = atlas.DbTypePostgre
+	genCode := &GenCode{conf: conf, codeGen: &codegen.CodeGen{}}
+	funcQuery := genCode.genFunc("Users", "insertBulk", pq)
+
+	require.Len(t, funcQuery.Args.Items, 1)
+	require.Equal(t, "rows", funcQuery.Args.Items[0].Name)
+	require.Equal(t, "[]Users_insertbulk", funcQuery.Args.Items[0].Type)
+	require.Equal(t, "rowAffected", funcQuery.Rets.Items[0].Name)
+	require.Contains(t, funcQuery.InlineCode, "I_to_arri(rows, func(row *Users_insertbulk) []any {")
+	require.Contains(t, funcQuery.InlineCode, "row.Name,")
+	require.Contains(t, funcQuery.InlineCode, "BulkMaxArgsPostgres")
+}
+
+func TestGenCopyFrom(t *testing.T) {
+	columns := []*parser.ParsedQueryField{parser.NewField("id", "int64"), parser.NewField("name", "string")}
+
+	genCode := &GenCode{codeGen: &codegen.CodeGen{}}
+	funcQuery := genCode.genCopyFrom("Users", "users", columns)
+
+	require.Equal(t, "CopyFromUsers", funcQuery.FuncName)
+	require.Equal(t, "[]Users_copyfromusers", funcQuery.Args.Items[0].Type)
+	require.Equal(t, "rowAffected", funcQuery.Rets.Items[0].Name)
+	require.Contains(t, funcQuery.InlineCode, `.CopyFrom(`+"\n\t\"users\",\n\t[]string{\"id\", \"name\"},")
+	require.Contains(t, funcQuery.InlineCode, "rows[i].Id,")
+}
+
+func TestGenFuncMulti(t *testing.T) {
+	update := &parser.ParsedQuery{}
+	update.Init("UPDATE users SET age = $1 WHERE id = $2")
+	update.QueryType = parser.QueryTypeUpdate
+	update.Arg = append(update.Arg, parser.NewField("val_age", "int32"), parser.NewField("id", "int64"))
+	update.Arg[1].IsNamed = true // :id, shared by both statements
+
+	sel := &parser.ParsedQuery{}
+	sel.Init("SELECT name FROM users WHERE id = $1")
+	sel.QueryType = parser.QueryTypeSelect
+	sel.Arg = append(sel.Arg, update.Arg[1])
+	sel.Ret = append(sel.Ret, parser.NewField("name", "string"))
+
+	pq := &parser.ParsedQuery{}
+	pq.Init(update.Query + "; " + sel.Query)
+	pq.QueryType = parser.QueryTypeSelect
+	pq.Arg = append(pq.Arg, update.Arg...)
+	pq.Arg = append(pq.Arg, sel.Arg...)
+	pq.Ret = sel.Ret
+	pq.Stmts = append(pq.Stmts, update, sel)
+
+	genCode := &GenCode{codeGen: &codegen.CodeGen{}}
+	funcQuery := genCode.genFunc("Users", "setAge", pq)
+
+	require.Len(t, funcQuery.Args.Items, 2)
+	require.Equal(t, "[]*Users_setage_2", funcQuery.Rets.Items[0].Type)
+	require.Contains(t, funcQuery.InlineCode, "t.job.Transaction(func(job *Job) (err error) {")
+	require.Contains(t, funcQuery.InlineCode, "if _, err = tx.setAge_1(val_age, id); err != nil {")
+	require.Contains(t, funcQuery.InlineCode, "setage_2s, err = tx.setAge_2(id)")
+}
+
+func TestGenEnum(t *testing.T) {
+	genCode := &GenCode{codeGen: &codegen.CodeGen{}}
+	genCode.codeGen.Package = "gen"
+	genCode.codeGen.AddItem(genCode.genEnum(&parser.Enum{GoType: "MemberRole", Values: []string{"owner", "read-only"}}))
+	code := genCode.codeGen.Code()
+
+	require.Contains(t, code, "type MemberRole string")
+	require.Contains(t, code, `MemberRoleReadOnly MemberRole = "read-only"`)
+	require.Contains(t, code, "case MemberRoleOwner, MemberRoleReadOnly:")
+	require.Contains(t, code, "func (t *MemberRole) Scan(")
+	require.Contains(t, code, "return ScanEnum(t, src)")
+	require.Contains(t, code, "func (t MemberRole) Value() (")
+	require.Contains(t, code, "return EnumValue(t)")
+}
+
+// newTestConf returns the config of a package gen dot importing db, with an empty schema of the db type.
+func newTestConf(dbType atlas.DbType) *config.Config {
+	conf := &config.Config{}
+	conf.Global.PackageName = "gen"
+	conf.Global.ClassName = "Gen"
+	conf.Global.Import = []*config.Import{{Alias: ".", Path: "github.com/gosuda/ornn/db"}}
+	conf.Schema.Init(dbType, &schema.Schema{})
+	return conf
+}
+
+// genTestCode returns the code generated for the queries of one group.
+func genTestCode(t *testing.T, conf *config.Config, group string, queries map[string]*parser.ParsedQuery) string {
+	t.Helper()
+	genQueries := &GenQueries{}
+	genQueries.Init(conf, nil)
+	genQueries.class[group] = queries
+
+	code, err := (&GenCode{}).code(conf, genQueries)
+	require.NoError(t, err)
+	return code
+}
+
+func TestGenCodeTypeImports(t *testing.T) {
+	conf := newTestConf(atlas.DbTypePostgre)
+	conf.Global.TypeOverrides = []*config.TypeOverride{
+		{DbType: "uuid", GoType: "uuid.UUID", Import: "github.com/google/uuid"},
+		{DbType: "numeric", GoType: "decimal.Decimal", Import: "github.com/shopspring/decimal"},
+	}
+
+	pq := &parser.ParsedQuery{}
+	pq.Init("SELECT id, attrs FROM accounts")
+	pq.QueryType = parser.QueryTypeSelect
+	pq.Ret = append(pq.Ret, parser.NewField("id", "uuid.UUID"), parser.NewField("attrs", "hstore.Hstore"))
+	code := genTestCode(t, conf, "accounts", map[string]*parser.ParsedQuery{"select": pq})
+	require.Contains(t, code, `"github.com/google/uuid"`)
+	require.Contains(t, code, `"github.com/lib/pq/hstore"`)
+	require.NotContains(t, code, "shopspring")
+}
+
+func TestGenCodePostgresArray(t *testing.T) {
+	conf := newTestConf(atlas.DbTypePostgre)
+
+	selectQuery := &parser.ParsedQuery{}
+	selectQuery.Init("SELECT id, tags FROM events WHERE id = ANY($1)")
+	selectQuery.QueryType = parser.QueryTypeSelect
+	selectQuery.Ret = append(selectQuery.Ret, parser.NewField("id", "int64"), parser.NewField("tags", "[]string"))
+	ids := parser.NewField("ids", "[]int64")
+	selectQuery.Arg = append(selectQuery.Arg, ids)
+
+	updateQuery := &parser.ParsedQuery{}
+	updateQuery.Init("UPDATE events SET tags = $1 WHERE id = $2")
+	updateQuery.QueryType = parser.QueryTypeUpdate
+	updateQuery.Arg = append(updateQuery.Arg, parser.NewField("tags", "[]string"), parser.NewField("id", "int64"))
+
+	code := genTestCode(t, conf, "events", map[string]*parser.ParsedQuery{"select": selectQuery, "update": updateQuery})
+	require.Contains(t, code, `"github.com/lib/pq"`)
+	require.Contains(t, code, "pq.Array(&scan.Tags)")
+	require.Contains(t, code, "pq.Array(ids)")
+	require.Contains(t, code, "pq.Array(tags)")
+	require.NotContains(t, code, "pq.Array(id)")
+}
+
+func TestGenCodeOptionalArgs(t *testing.T) {
+	conf := newTestConf(atlas.DbTypePostgre)
+
+	pq := &parser.ParsedQuery{}
+	pq.Init("SELECT id FROM users WHERE tenant_id = $1 AND name = $2")
+	pq.QueryType = parser.QueryTypeSelect
+	pq.Ret = append(pq.Ret, parser.NewField("id", "int64"))
+	name := parser.NewField("where_name", "*string")
+	name.IsOptional = true
+	pq.Arg = append(pq.Arg, parser.NewField("where_tenant_id", "int64"), name)
+	code := genTestCode(t, conf, "users", map[string]*parser.ParsedQuery{"search": pq})
+	require.Contains(t, code, "where_name *string")
+	require.Contains(t, code, "NewOptionalArg(where_name)")
+	require.Contains(t, code, "sql, args, err = OptionalWhere(sql, args)")
+	require.NotContains(t, code, "NewOptionalArg(where_tenant_id)")
+}
+
+func TestGenCodeUpdateNullIgnore(t *testing.T) {
+	conf := newTestConf(atlas.DbTypeMySQL)
+
+	pq := &parser.ParsedQuery{}
+	pq.Init("UPDATE users SET name = ?, age = ? WHERE id = ?")
+	pq.QueryType = parser.QueryTypeUpdate
+	pq.UpdateNullIgnore = true
+	name, age := parser.NewField("set_name", "*string"), parser.NewField("set_age", "*int32")
+	name.IsOptional, age.IsOptional = true, true
+	pq.Arg = append(pq.Arg, name, age, parser.NewField("where_id", "int64"))
+	code := genTestCode(t, conf, "users", map[string]*parser.ParsedQuery{"patch": pq})
+	require.Contains(t, code, "NewOptionalArg(set_age)")
+	require.Contains(t, code, "sql, args, err = OptionalSet(sql, args)")
+	require.Contains(t, code, "return 0, err")
+}
+
+func TestGenCodeTpl(t *testing.T) {
+	conf := newTestConf(atlas.DbTypeMySQL)
+
+	pq := &parser.ParsedQuery{}
+	pq.Init("DELETE FROM logs_%s WHERE id = ?")
+	pq.QueryType = parser.QueryTypeDelete
+	pq.Tpl = append(pq.Tpl, parser.NewField("shard", "string"))
+	pq.Arg = append(pq.Arg, parser.NewField("id", "int64"))
+	code := genTestCode(t, conf, "logs", map[string]*parser.ParsedQuery{"delete": pq})
+	require.Contains(t, code, "tpl_shard string")
+	require.Contains(t, code, "if err = CheckTpl(\"shard\", tpl_shard); err != nil {\n\t\treturn 0, err\n\t}")
+	require.Contains(t, code, "tpl_shard,\n\t)")
+}
+
+func TestGenCodeOrderBy(t *testing.T) {
+	conf := newTestConf(atlas.DbTypeMySQL)
+
+	pq := &parser.ParsedQuery{}
+	pq.Init("SELECT id FROM users LIMIT ?")
+	pq.QueryType = parser.QueryTypeSelect
+	pq.Ret = append(pq.Ret, parser.NewField("id", "int64"))
+	pq.Arg = append(pq.Arg, parser.NewField("limit", "int64"))
+	pq.OrderBy = []string{"name", "created_at"}
+	code := genTestCode(t, conf, "users", map[string]*parser.ParsedQuery{"list": pq})
+	require.Contains(t, code, "type Users_listOrderBy string")
+	require.Contains(t, code, `Users_listOrderByCreatedAt Users_listOrderBy = "created_at"`)
+	require.Contains(t, code, "orderBy Users_listOrderBy,")
+	require.Contains(t, code, "direction Direction,")
+	require.Contains(t, code, "sql, err = OrderBy(sql, orderBy, direction)\n\tif err != nil {\n\t\treturn nil, err")
+	require.NotContains(t, code, "func (t *Users_listOrderBy) Scan")
+}
+
+func TestGenCodePaginate(t *testing.T) {
+	conf := newTestConf(atlas.DbTypePostgre)
+
+	pq := &parser.ParsedQuery{}
+	pq.Init("SELECT id, name FROM users WHERE name = $1 ORDER BY name, id")
+	pq.QueryType = parser.QueryTypeSelect
+	pq.Ret = append(pq.Ret, parser.NewField("id", "int64"), parser.NewField("name", "string"))
+	pq.Arg = append(pq.Arg, parser.NewField("where_name", "string"))
+	pq.Page = &parser.Page{Columns: []string{"name", "id"}, Fields: []string{"name", "id"}, Numbered: true}
+	code := genTestCode(t, conf, "users", map[string]*parser.ParsedQuery{"list": pq})
+	require.Contains(t, code, "func (t *Users) List(")
+	require.Contains(t, code, "func (t *Users) ListPage(")
+	require.Contains(t, code, "cursor string,")
+	require.Contains(t, code, "limit int,")
+	require.Contains(t, code, "rows []*Users_list,")
+	require.Contains(t, code, "next string,")
+	require.Contains(t, code, `keyset := Keyset{Columns: []string{"name", "id"}, Desc: false, Numbered: true}`)
+	require.Contains(t, code, "after := &Users_list{}")
+	require.Contains(t, code, "sql, args, err = keyset.Page(sql, args, cursor, limit, &after.Name, &after.Id)")
+	require.Contains(t, code, "next, err = keyset.Cursor(last.Name, last.Id)")
+}
@@ -1,9 +1,17 @@
 package gen
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"github.com/gosuda/ornn/atlas"
 	"github.com/gosuda/ornn/config"
+	"github.com/gosuda/ornn/db"
+	"github.com/gosuda/ornn/gen/util"
 	"github.com/gosuda/ornn/parser"
 )
 
@@ -11,13 +19,17 @@ type GenQueries struct {
 	conf *config.Config
 	psr  parser.Parser
 
-	class map[string]map[string]*parser.ParsedQuery
+	class    map[string]map[string]*parser.ParsedQuery
+	copyFrom map[string][]*parser.ParsedQueryField // table columns of the CopyFrom loaders
+
+	diagnostics Diagnostics // errors of the queries, in config order
 }
 
 func (t *GenQueries) Init(conf *config.Config, psr parser.Parser) {
 	t.conf = conf
 	t.psr = psr
 	t.class = make(map[string]map[string]*parser.ParsedQuery)
+	t.copyFrom = make(map[string][]*parser.ParsedQueryField)
 }
 
 func (t *GenQueries) SetData() (err error) {
@@ -32,9 +44,57 @@ func (t *GenQueries) SetData() (err error) {
 			return err
 		}
 	}
+
+	// copy from loaders
+	if t.conf.Global.CopyFrom == true {
+		return t.SetDataCopyFrom()
+	}
 	return nil
 }
 
+// SetDataCopyFrom collects the columns of every table for the CopyFrom<Table> loaders,
+// leaving out the columns COPY can not write
+func (t *GenQueries) SetDataCopyFrom() error {
+	if t.conf.Schema.DbType != atlas.DbTypePostgre && t.conf.Schema.DbType != atlas.DbTypeCockroachDB {
+		return fmt.Errorf("copy_from option is only valid for postgres")
+	}
+	for _, table := range t.conf.Schema.Tables {
+		columns := make([]string, 0, len(table.Columns))
+		for _, col := range table.Columns {
+			if copyColumn(col) == true {
+				columns = append(columns, fmt.Sprintf(`"%s"`, col.Name))
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		parseQuery, err := t.psr.Parse(fmt.Sprintf(`SELECT %s FROM "%s"`, strings.Join(columns, ", "), table.Name))
+		if err != nil {
+			return err
+		}
+		if t.class[table.Name] == nil {
+			t.class[table.Name] = make(map[string]*parser.ParsedQuery)
+		}
+		t.copyFrom[table.Name] = parseQuery.Ret
+	}
+	return nil
+}
+
+// copyColumn reports whether COPY can write the column, not a generated column or an identity GENERATED ALWAYS
+func copyColumn(col *schema.Column) bool {
+	for _, attr := range col.Attrs {
+		switch attr := attr.(type) {
+		case *schema.GeneratedExpr:
+			return false
+		case *postgres.Identity:
+			if strings.EqualFold(attr.Generation, "ALWAYS") == true {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func (t *GenQueries) SetDataGroup(groupName string, queries []*config.Query) (err error) {
 	if t.class == nil {
 		t.class = make(map[string]map[string]*parser.ParsedQuery)
@@ -53,10 +113,448 @@ func (t *GenQueries) SetDataGroup(groupName string, queries []*config.Query) (er
 }
 
 func (t *GenQueries) SetDataQuery(groupName string, query *config.Query) (parseQuery *parser.ParsedQuery, err error) {
-	parseQuery, err = t.psr.Parse(query.Sql)
+	// #tpl# segments are parsed as their default (#name/default#) or name
+	tpls, err := parseTpls(query.Sql, query.TplValues)
 	if err != nil {
-		query.ErrParser = fmt.Sprintf("%v", err)
+		query.ErrQuery = fmt.Sprintf("%v", err)
+		t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+		return nil, nil
+	}
+	sql := query.Sql
+	if len(tpls) > 0 {
+		sql = util.ReplaceInDelimiter(query.Sql, util.TplDelimiter, util.TplSplit)
+	}
+
+	parseQuery, err = t.psr.Parse(sql)
+	if err != nil {
+		query.ErrParser = fmt.Sprintf("query %s.%s | %v", groupName, query.Name, err)
+		if len(tpls) > 0 {
+			// located in the sql as written, before the tpl segments are replaced
+			offset := util.InDelimiterOffset(query.Sql, util.TplDelimiter, util.TplSplit, parser.ErrorOffset(sql, err))
+			err = &parser.Error{Msg: err.Error(), Offset: offset}
+		}
+		t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+		return nil, nil
+	}
+	if err := t.setTpls(query.Sql, tpls, parseQuery); err != nil {
+		query.ErrQuery = fmt.Sprintf("%v", err)
+		t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+		return nil, nil
+	}
+
+	// options
+	if query.Bulk == true {
+		parseQuery.InsertMulti = true
+	}
+	if len(query.OrderBy) > 0 {
+		if err := t.setOrderBy(groupName, query.OrderBy, parseQuery); err != nil {
+			query.ErrQuery = fmt.Sprintf("%v", err)
+			t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+			return nil, nil
+		}
+	}
+	if query.Paginate == true {
+		if err := t.setPage(groupName, parseQuery); err != nil {
+			query.ErrQuery = fmt.Sprintf("%v", err)
+			t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+			return nil, nil
+		}
+	}
+	if query.UpdateNullIgnore == true {
+		parseQuery.UpdateNullIgnore = true
+		if err := setNullIgnoreArgs(parseQuery); err != nil {
+			query.ErrQuery = fmt.Sprintf("%v", err)
+			t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+			return nil, nil
+		}
+	}
+	if err := setCustomFieldTypes(groupName, query.CustomFieldTypes, t.conf.Schema.Nullable, parseQuery); err != nil {
+		query.ErrQuery = fmt.Sprintf("%v", err)
+		t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+		return nil, nil
+	}
+	if err := setOptionalArgs(query.Optional, parseQuery); err != nil {
+		query.ErrQuery = fmt.Sprintf("%v", err)
+		t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
+		return nil, nil
+	}
+
+	// validate
+	if err := t.validateQuery(parseQuery); err != nil {
+		query.ErrQuery = fmt.Sprintf("%v", err)
+		t.diagnostics = append(t.diagnostics, newDiagnostic(t.conf.Path, groupName+"."+query.Name, query.Sql, err))
 		return nil, nil
 	}
 	return parseQuery, nil
 }
+
+// argPrefixes are the prefixes the parsers put before the column name of an arg, longest first
+var argPrefixes = []string{"dup_where_", "conflict_", "having_", "where_", "order_", "dup_", "set_", "val_"}
+
+// setCustomFieldTypes sets the custom field types to the args and rets bound to their columns.
+// json columns are wrapped in JSON[T] to be marshaled from and to the custom type.
+func setCustomFieldTypes(groupName string, customs []*config.CustomFieldType, nullable string, parseQuery *parser.ParsedQuery) error {
+	if len(customs) == 0 {
+		return nil
+	}
+
+	used := make(map[*config.CustomFieldType]bool, len(customs))
+	queries := append([]*parser.ParsedQuery{parseQuery}, parseQuery.Stmts...)
+	for _, query := range queries {
+		for _, fields := range [][]*parser.ParsedQueryField{query.Arg, query.Ret} {
+			for _, field := range fields {
+				custom := customFieldType(groupName, field.Name, customs)
+				if custom == nil {
+					continue
+				}
+				used[custom] = true
+				switch {
+				case strings.TrimPrefix(field.GoType, "*") == "json.RawMessage":
+					field.GoType = "JSON[" + custom.CustomType + "]"
+				case isNullable(field.GoType) == true:
+					field.GoType = parser.NullableType(custom.CustomType, nullable)
+				default:
+					field.GoType = custom.CustomType
+				}
+			}
+		}
+	}
+
+	for _, custom := range customs {
+		if used[custom] == false {
+			return fmt.Errorf("custom field type %s.%s matches no arg or column of the query", custom.TableName, custom.FieldName)
+		}
+	}
+	return nil
+}
+
+// isNullable reports whether the go type is the nullable representation of a column type, see parser.NullableType
+func isNullable(goType string) bool {
+	return strings.HasPrefix(goType, "*") || strings.HasPrefix(goType, "sql.Null") || strings.HasPrefix(goType, "Null[")
+}
+
+// parseTpls returns the #name# and #name/default# segments of the sql as string tpls.
+func parseTpls(sql string, values map[string][]string) ([]*parser.ParsedQueryField, error) {
+	segments, err := util.ExportBetweenDelimiter(sql, util.TplDelimiter)
+	if err != nil {
+		return nil, fmt.Errorf("tpl | %v", err)
+	}
+	tpls := make([]*parser.ParsedQueryField, 0, len(segments))
+	for _, segment := range segments {
+		name, _, _ := strings.Cut(segment, util.TplSplit)
+		if isTplName(name) == false {
+			return nil, fmt.Errorf("tpl | %q is not a tpl name, #name# or #name/default#", segment)
+		}
+		tpl := parser.NewField(name, "string")
+		tpl.Allowed = values[name]
+		tpls = append(tpls, tpl)
+	}
+	for name := range values {
+		if slices.ContainsFunc(tpls, func(tpl *parser.ParsedQueryField) bool { return tpl.Name == name }) == false {
+			return nil, fmt.Errorf("tpl_values %s matches no tpl of the query", name)
+		}
+	}
+	return tpls, nil
+}
+
+// setTpls sets the tpls of the query, and its sql to the fmt format of the tpls with named args rewritten as the parser did.
+func (t *GenQueries) setTpls(sql string, tpls []*parser.ParsedQueryField, parseQuery *parser.ParsedQuery) error {
+	if len(tpls) == 0 {
+		return nil
+	}
+	if len(parseQuery.Stmts) > 0 {
+		return fmt.Errorf("tpl does not support multi-statement queries")
+	}
+	format := util.ReplaceBetweenDelimiter(strings.ReplaceAll(sql, "%", "%%"), util.TplDelimiter, util.TplAfter)
+	numbered := t.conf.Schema.DbType == atlas.DbTypePostgre || t.conf.Schema.DbType == atlas.DbTypeCockroachDB
+	format, _, err := parser.RewriteNamedArgs(format, numbered)
+	if err != nil {
+		return err
+	}
+	parseQuery.Query = format
+	parseQuery.Tpl = tpls
+	return nil
+}
+
+func isTplName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		ch := name[i]
+		if ch != '_' && (ch < 'a' || ch > 'z') && (ch < 'A' || ch > 'Z') && (ch < '0' || ch > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// setOrderBy sets the sortable columns of the order_by option, each a column of the schema.
+// A column without table is looked up in the table of the group, or in every table if the group is not a table.
+func (t *GenQueries) setOrderBy(groupName string, columns []string, parseQuery *parser.ParsedQuery) error {
+	switch {
+	case len(parseQuery.Stmts) > 0:
+		return fmt.Errorf("order_by option does not support multi-statement queries")
+	case parseQuery.QueryType != parser.QueryTypeSelect || len(parseQuery.Ret) == 0:
+		return fmt.Errorf("order_by option is only valid for select")
+	}
+
+	for _, column := range columns {
+		tableName, columnName, ok := strings.Cut(column, ".")
+		found := false
+		switch {
+		case t.conf.Schema.Schema == nil:
+		case ok == true:
+			_, found = t.conf.Schema.GetFieldType(tableName, columnName)
+		default:
+			if _, exist := t.conf.Schema.Table(groupName); exist {
+				_, found = t.conf.Schema.GetFieldType(groupName, column)
+			} else {
+				types, _ := t.conf.Schema.GetFieldTypeAll(column)
+				found = len(types) > 0
+			}
+		}
+		if found == false {
+			return fmt.Errorf("order_by column %s is not in the schema", column)
+		}
+	}
+	parseQuery.OrderBy = columns
+	return nil
+}
+
+// setPage sets the keyset of the paginate option, the ORDER BY columns then the primary key of the group table.
+// Each key column must be returned by the select, the cursor of the next page is built from the last row.
+// A nullable key column is rejected, NULL compares to nothing and the rows after it would never be paged.
+func (t *GenQueries) setPage(groupName string, parseQuery *parser.ParsedQuery) error {
+	switch {
+	case len(parseQuery.Stmts) > 0:
+		return fmt.Errorf("paginate option does not support multi-statement queries")
+	case parseQuery.QueryType != parser.QueryTypeSelect || len(parseQuery.Ret) == 0:
+		return fmt.Errorf("paginate option is only valid for select")
+	case parseQuery.SelectSingle == true:
+		return fmt.Errorf("paginate option is not valid for a single row select")
+	case len(parseQuery.OrderBy) > 0:
+		return fmt.Errorf("paginate option can not be combined with order_by")
+	}
+
+	var primaryKey []string
+	if t.conf.Schema.Schema != nil {
+		if table, exist := t.conf.Schema.Table(groupName); exist && table.PrimaryKey != nil {
+			for _, part := range table.PrimaryKey.Parts {
+				if part.C != nil {
+					primaryKey = append(primaryKey, part.C.Name)
+				}
+			}
+		}
+	}
+	numbered := t.conf.Schema.DbType == atlas.DbTypePostgre || t.conf.Schema.DbType == atlas.DbTypeCockroachDB
+	keyset, err := db.NewKeyset(parseQuery.Query, primaryKey, numbered)
+	if err != nil {
+		return err
+	}
+
+	page := &parser.Page{Columns: keyset.Columns, Desc: keyset.Desc, Numbered: keyset.Numbered}
+	for _, column := range keyset.Columns {
+		field := pageField(column, parseQuery.Ret)
+		if field == nil {
+			return fmt.Errorf("paginate key column %s is not returned by the select", column)
+		}
+		if field.IsSlice == true || strings.HasPrefix(field.GoType, "[]") {
+			return fmt.Errorf("paginate key column %s can not be a list", column)
+		}
+		if isNullable(field.GoType) == true {
+			return fmt.Errorf("paginate key column %s can not be nullable", column)
+		}
+		page.Fields = append(page.Fields, field.Name)
+	}
+	parseQuery.Page = page
+	return nil
+}
+
+// pageField returns the ret of a key column, by name, by column name without table or by table__column.
+func pageField(column string, rets []*parser.ParsedQueryField) *parser.ParsedQueryField {
+	_, columnName, _ := strings.Cut(column, ".")
+	for _, name := range []string{column, strings.ReplaceAll(column, ".", "__"), columnName} {
+		for _, ret := range rets {
+			if name != "" && ret.Name == name {
+				return ret
+			}
+		}
+	}
+	return nil
+}
+
+// setOptionalArgs marks the args of the optional option, named by arg name or by the column of a where_ arg.
+// Optional args are pointers, a nil arg drops its WHERE predicate at runtime.
+func setOptionalArgs(optional []string, parseQuery *parser.ParsedQuery) error {
+	for _, name := range optional {
+		found := false
+		for _, arg := range parseQuery.Arg {
+			if arg.Name != name && arg.Name != "where_"+name {
+				continue
+			}
+			found = true
+			arg.IsOptional = true
+			if strings.HasPrefix(arg.GoType, "*") == false {
+				arg.GoType = "*" + arg.GoType
+			}
+		}
+		if found == false {
+			return fmt.Errorf("optional arg %s matches no arg of the query", name)
+		}
+	}
+	return nil
+}
+
+// customFieldType returns the custom field type of the column an arg or ret is bound to.
+// The column is the name without the arg prefix (where_, set_ ...), or table__column for joined tables.
+// A custom field type without table name matches the column of any table.
+func customFieldType(groupName, name string, customs []*config.CustomFieldType) *config.CustomFieldType {
+	tableName, fieldName := groupName, name
+	for _, prefix := range argPrefixes {
+		if strings.HasPrefix(fieldName, prefix) {
+			fieldName = fieldName[len(prefix):]
+			break
+		}
+	}
+	if i := strings.Index(fieldName, "__"); i != -1 {
+		tableName, fieldName = fieldName[:i], fieldName[i+len("__"):]
+	}
+
+	for _, custom := range customs {
+		if (custom.FieldName == name || custom.FieldName == fieldName) &&
+			(custom.TableName == "" || custom.TableName == tableName) {
+			return custom
+		}
+	}
+	return nil
+}
+
+// validateQuery checks the parsed query against what will be generated for it.
+func (t *GenQueries) validateQuery(parseQuery *parser.ParsedQuery) error {
+	// every placeholder in the sql must be fed by exactly one generated arg, counted in the sql apart from the parser
+	numbered := t.conf.Schema.DbType == atlas.DbTypePostgre || t.conf.Schema.DbType == atlas.DbTypeCockroachDB
+	stmts, placeholder := parseQuery.Stmts, 0
+	if len(stmts) == 0 {
+		stmts = []*parser.ParsedQuery{parseQuery}
+	}
+	for _, stmt := range stmts {
+		placeholder += db.CountArgs(stmt.Query, numbered)
+	}
+	if len(parseQuery.Arg) != placeholder {
+		return fmt.Errorf("placeholder count mismatch | sql has %d placeholders but %d args are generated", placeholder, len(parseQuery.Arg))
+	}
+
+	// optional args are dropped with their WHERE predicate
+	if err := validateOptionalArgs(parseQuery); err != nil {
+		return err
+	}
+
+	// bulk insert repeats the VALUES row, so every arg must be in it
+	if parseQuery.InsertMulti == true {
+		switch {
+		case len(parseQuery.Stmts) > 0:
+			return fmt.Errorf("bulk option does not support multi-statement queries")
+		case parseQuery.QueryType != parser.QueryTypeInsert:
+			return fmt.Errorf("bulk option is only valid for insert")
+		case len(parseQuery.Ret) > 0:
+			return fmt.Errorf("bulk option does not support returning")
+		case len(parseQuery.Arg) == 0:
+			return fmt.Errorf("bulk option needs at least one arg in values")
+		}
+		for _, arg := range parseQuery.Arg {
+			if arg.IsSlice == true {
+				return fmt.Errorf("bulk option does not support slice arg %s", arg.Name)
+			}
+		}
+		if _, err := db.BulkValues(parseQuery.Query, 1, len(parseQuery.Arg)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setNullIgnoreArgs marks the args of the SET assignments of an update_null_ignore update optional.
+// Optional args are pointers, the assignment of a nil arg is dropped at runtime.
+func setNullIgnoreArgs(parseQuery *parser.ParsedQuery) error {
+	switch {
+	case len(parseQuery.Stmts) > 0:
+		return fmt.Errorf("update_null_ignore option does not support multi-statement queries")
+	case parseQuery.QueryType != parser.QueryTypeUpdate:
+		return fmt.Errorf("update_null_ignore option is only valid for update")
+	}
+
+	found := false
+	for _, arg := range parseQuery.Arg {
+		if dropped, _ := droppedArg(parseQuery, arg.Name, true, false); arg.IsSlice == true || dropped == false {
+			continue
+		}
+		found = true
+		arg.IsOptional = true
+		if strings.HasPrefix(arg.GoType, "*") == false {
+			arg.GoType = "*" + arg.GoType
+		}
+	}
+	if found == false {
+		return fmt.Errorf("update_null_ignore option needs args in SET")
+	}
+	return nil
+}
+
+// droppedArg reports whether every placeholder of the named arg is dropped when it is nil,
+// with its SET assignment (db.OptionalSet) or its WHERE predicate (db.OptionalWhere, whose error is returned).
+func droppedArg(parseQuery *parser.ParsedQuery, name string, set, where bool) (bool, error) {
+	args := make([]any, len(parseQuery.Arg))
+	for i, arg := range parseQuery.Arg {
+		args[i] = 0
+		if arg.Name == name {
+			args[i] = db.OptionalArg{}
+		}
+	}
+	query := parseQuery.Query
+	if set == true {
+		// an error means no assignment is left, so the arg was in SET
+		var err error
+		if query, args, err = db.OptionalSet(query, args); err != nil {
+			return true, nil
+		}
+	}
+	if where == true {
+		var err error
+		if query, args, err = db.OptionalWhere(query, args); err != nil {
+			return false, err
+		}
+	}
+	for _, arg := range args {
+		if optional, ok := arg.(db.OptionalArg); arg == nil || (ok && optional.Val == nil) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// validateOptionalArgs checks every optional arg can be dropped with a SET assignment or a top level WHERE predicate.
+func validateOptionalArgs(parseQuery *parser.ParsedQuery) error {
+	for _, arg := range parseQuery.Arg {
+		if arg.IsOptional == false {
+			continue
+		}
+		switch {
+		case len(parseQuery.Stmts) > 0:
+			return fmt.Errorf("optional option does not support multi-statement queries")
+		case parseQuery.QueryType != parser.QueryTypeSelect && parseQuery.QueryType != parser.QueryTypeUpdate && parseQuery.QueryType != parser.QueryTypeDelete:
+			return fmt.Errorf("optional option is only valid for select, update and delete")
+		case arg.IsSlice == true, strings.HasPrefix(arg.GoType, "*[]") && arg.GoType != "*[]byte":
+			return fmt.Errorf("optional option does not support slice arg %s", arg.Name)
+		}
+
+		dropped, err := droppedArg(parseQuery, arg.Name, parseQuery.UpdateNullIgnore, true)
+		if errors.Is(err, db.ErrOptionalOr) {
+			return fmt.Errorf("optional arg %s is in a WHERE with a top level OR, put the ORs in parentheses", arg.Name)
+		}
+		if err != nil || dropped == false {
+			return fmt.Errorf("optional arg %s is not in a top level WHERE predicate", arg.Name)
+		}
+	}
+	return nil
+}
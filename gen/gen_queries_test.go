@@ -0,0 +1,457 @@
+package gen
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
+	"github.com/gosuda/ornn/atlas"
+	"github.com/gosuda/ornn/config"
+	"github.com/gosuda/ornn/gen/util"
+	"github.com/gosuda/ornn/parser"
+	"github.com/gosuda/ornn/parser/parser_mysql"
+	"github.com/gosuda/ornn/parser/parser_postgres"
+	"github.com/gosuda/ornn/parser/parser_sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+// stubParser returns a fixed parse result regardless of the sql.
+type stubParser struct {
+	pq *parser.ParsedQuery
+}
+
+func (t *stubParser) Parse(sql string) (*parser.ParsedQuery, error) {
+	t.pq.Query = sql
+	return t.pq, nil
+}
+
+// newStubConf returns the config of an empty postgres schema, the stub queries are written with $n placeholders.
+func newStubConf() *config.Config {
+	conf := &config.Config{}
+	conf.Schema.Init(atlas.DbTypePostgre, &schema.Schema{})
+	return conf
+}
+
+func newStubQuery(args ...string) *parser.ParsedQuery {
+	pq := &parser.ParsedQuery{}
+	pq.Init("")
+	pq.QueryType = parser.QueryTypeUpdate
+	for _, arg := range args {
+		pq.Arg = append(pq.Arg, parser.NewField(arg, "string"))
+	}
+	return pq
+}
+
+func TestSetDataQueryPlaceholderMismatch(t *testing.T) {
+	genQueries := &GenQueries{}
+	genQueries.Init(newStubConf(), &stubParser{pq: newStubQuery("val_name", "val_age")})
+
+	query := &config.Query{Name: "update", Sql: "UPDATE users SET name = $1, age = 3"}
+	pq, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, pq)
+	require.Contains(t, query.ErrQuery, "1 placeholders but 2 args")
+}
+
+func TestSetDataQueryPlaceholderMatch(t *testing.T) {
+	genQueries := &GenQueries{}
+	genQueries.Init(newStubConf(), &stubParser{pq: newStubQuery("val_name", "val_age")})
+
+	query := &config.Query{Name: "update", Sql: "UPDATE users SET name = $1, age = $2"}
+	pq, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, pq)
+	require.Empty(t, query.ErrQuery)
+}
+
+func TestSetDataQueryPlaceholderParsers(t *testing.T) {
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewStringColumn("name", "text"))
+	for _, test := range []struct {
+		dbType atlas.DbType
+		new    func(sch *config.Schema) parser.Parser
+		sql    string
+	}{
+		{atlas.DbTypeMySQL, parser_mysql.New, "UPDATE users SET name = :name WHERE id = :id AND name <> :name"},
+		{atlas.DbTypeSQLite, parser_sqlite.New, "UPDATE users SET name = :name WHERE id = :id AND name <> :name"},
+		// a name repeated is one $n, counted once
+		{atlas.DbTypePostgre, parser_postgres.New, "UPDATE users SET name = :name WHERE id = :id AND name <> :name"},
+		// the $n of each statement start at $1
+		{atlas.DbTypePostgre, parser_postgres.New, "UPDATE users SET name = :name WHERE id = :id; SELECT id, name FROM users WHERE id = :id"},
+	} {
+		conf := &config.Config{}
+		conf.Schema.Init(test.dbType, schema.New("public").AddTables(users))
+		genQueries := &GenQueries{}
+		genQueries.Init(conf, test.new(&conf.Schema))
+
+		query := &config.Query{Name: "rename", Sql: test.sql}
+		parsed, err := genQueries.SetDataQuery("users", query)
+		require.NoError(t, err)
+		require.Empty(t, query.ErrQuery, test.sql)
+		require.NotNil(t, parsed)
+	}
+}
+
+func TestSetDataQueryBulk(t *testing.T) {
+	pq := newStubQuery("val_name", "val_age")
+	pq.QueryType = parser.QueryTypeInsert
+	genQueries := &GenQueries{}
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+
+	query := &config.Query{Name: "insertBulk", Sql: "INSERT INTO users (name, age) VALUES ($1, $2)", Bulk: true}
+	parsed, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.True(t, parsed.InsertMulti)
+
+	// the conflict update arg can not be repeated per row
+	query = &config.Query{Name: "upsertBulk", Sql: "INSERT INTO users (name) VALUES ($1) ON CONFLICT (name) DO UPDATE SET age = $2", Bulk: true}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "outside the VALUES row")
+
+	pq.QueryType = parser.QueryTypeUpdate
+	query = &config.Query{Name: "updateBulk", Sql: "UPDATE users SET name = $1, age = $2", Bulk: true}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "only valid for insert")
+}
+
+func TestSetDataCopyFrom(t *testing.T) {
+	conf := &config.Config{}
+	conf.Global.CopyFrom = true
+	conf.Schema.DbType = atlas.DbTypeMySQL
+	conf.Schema.Schema = schema.New("public").AddTables(schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint")))
+
+	pq := newStubQuery()
+	pq.Ret = append(pq.Ret, parser.NewField("id", "int64"))
+	genQueries := &GenQueries{}
+	genQueries.Init(conf, &stubParser{pq: pq})
+	require.Error(t, genQueries.SetDataCopyFrom())
+
+	conf.Schema.DbType = atlas.DbTypePostgre
+	require.NoError(t, genQueries.SetDataCopyFrom())
+	require.Equal(t, pq.Ret, genQueries.copyFrom["users"])
+	require.NotNil(t, genQueries.class["users"])
+
+	// generated and GENERATED ALWAYS identity columns are not written by COPY
+	users := schema.NewTable("users").AddColumns(
+		schema.NewIntColumn("id", "bigint").AddAttrs(&postgres.Identity{Generation: "ALWAYS"}),
+		schema.NewStringColumn("name", "text"),
+		schema.NewStringColumn("lower_name", "text").SetGeneratedExpr(&schema.GeneratedExpr{Expr: "lower(name)", Type: "STORED"}),
+		schema.NewIntColumn("seq", "bigint").AddAttrs(&postgres.Identity{Generation: "BY DEFAULT"}),
+	)
+	conf = &config.Config{}
+	conf.Global.CopyFrom = true
+	conf.Schema.Init(atlas.DbTypePostgre, schema.New("public").AddTables(users))
+	genQueries = &GenQueries{}
+	genQueries.Init(conf, parser_postgres.New(&conf.Schema))
+	require.NoError(t, genQueries.SetDataCopyFrom())
+	require.Len(t, genQueries.copyFrom["users"], 2)
+	require.Equal(t, "name", genQueries.copyFrom["users"][0].Name)
+	require.Equal(t, "seq", genQueries.copyFrom["users"][1].Name)
+}
+
+type errParser struct {
+	err error
+}
+
+func (t *errParser) Parse(sql string) (*parser.ParsedQuery, error) {
+	return nil, t.err
+}
+
+func TestSetDataQueryParserError(t *testing.T) {
+	genQueries := &GenQueries{}
+	genQueries.Init(&config.Config{}, &errParser{err: errors.New("parser error | unknown column nmae in table users, did you mean name?")})
+
+	query := &config.Query{Name: "selectByName", Sql: "SELECT * FROM users WHERE nmae = ?"}
+	pq, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, pq)
+	require.Equal(t, "query users.selectByName | parser error | unknown column nmae in table users, did you mean name?", query.ErrParser)
+
+	// the offset in the parsed sql is located in the sql as written, before the tpl is replaced
+	sql := "SELECT * FROM #table/users# ORDER BY BY id"
+	offset := strings.LastIndex(util.ReplaceInDelimiter(sql, "#", "/"), "BY")
+	genQueries.Init(&config.Config{}, &errParser{err: &parser.Error{Msg: "parser error | syntax error", Offset: offset}})
+	query = &config.Query{Name: "list", Sql: sql}
+	pq, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, pq)
+	diag := genQueries.diagnostics[len(genQueries.diagnostics)-1]
+	require.Equal(t, strings.LastIndex(sql, "BY")+1, diag.Column)
+}
+
+func TestSetDataQueryCustomFieldTypes(t *testing.T) {
+	pq := newStubQuery("set_profile", "where_id")
+	pq.Arg[0].GoType = "json.RawMessage"
+	pq.Ret = append(pq.Ret, parser.NewField("profile", "json.RawMessage"), parser.NewField("status", "string"), parser.NewField("orgs__settings", "json.RawMessage"))
+	genQueries := &GenQueries{}
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+
+	query := &config.Query{Name: "update", Sql: "UPDATE users SET profile = $1 WHERE id = $2 RETURNING ..."}
+	query.AddCustomType("users", "profile", "Profile")
+	query.AddCustomType("", "status", "Status")
+	query.AddCustomType("orgs", "settings", "map[string]any")
+	pq, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Equal(t, "JSON[Profile]", pq.Arg[0].GoType)
+	require.Equal(t, "string", pq.Arg[1].GoType)
+	require.Equal(t, "JSON[Profile]", pq.Ret[0].GoType)
+	require.Equal(t, "Status", pq.Ret[1].GoType)
+	require.Equal(t, "JSON[map[string]any]", pq.Ret[2].GoType)
+
+	// a nullable column keeps the nullable representation
+	pq = newStubQuery("where_id")
+	pq.Ret = append(pq.Ret, parser.NewField("status", "*string"), parser.NewField("level", "sql.NullInt64"))
+	conf := newStubConf()
+	conf.Schema.Nullable = config.NullableGeneric
+	genQueries.Init(conf, &stubParser{pq: pq})
+	query = &config.Query{Name: "get", Sql: "SELECT status, level FROM users WHERE id = $1"}
+	query.AddCustomType("", "status", "Status")
+	query.AddCustomType("", "level", "Level")
+	pq, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Equal(t, "Null[Status]", pq.Ret[0].GoType)
+	require.Equal(t, "Null[Level]", pq.Ret[1].GoType)
+
+	// a column not in the query
+	query = &config.Query{Name: "update", Sql: "UPDATE users SET name = $1 WHERE id = $2"}
+	query.AddCustomType("users", "metadata", "Metadata")
+	genQueries.Init(newStubConf(), &stubParser{pq: newStubQuery("set_name", "where_id")})
+	pq, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, pq)
+	require.Equal(t, "custom field type users.metadata matches no arg or column of the query", query.ErrQuery)
+}
+
+func TestSetDataQueryOptional(t *testing.T) {
+	pq := newStubQuery("where_tenant_id", "where_name", "where_age")
+	pq.QueryType = parser.QueryTypeSelect
+	genQueries := &GenQueries{}
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+
+	query := &config.Query{Name: "search", Sql: "SELECT * FROM users WHERE tenant_id = $1 AND name = $2 AND age > $3", Optional: []string{"name", "where_age"}}
+	parsed, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.Empty(t, query.ErrQuery)
+	require.False(t, parsed.Arg[0].IsOptional)
+	require.Equal(t, []string{"string", "*string", "*string"}, []string{parsed.Arg[0].GoType, parsed.Arg[1].GoType, parsed.Arg[2].GoType})
+
+	// the predicate of an optional arg is dropped with the whole OR
+	pq = newStubQuery("where_tenant_id", "where_name", "where_age")
+	pq.QueryType = parser.QueryTypeSelect
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+	query = &config.Query{Name: "search", Sql: "SELECT * FROM users WHERE tenant_id = $1 AND (name = $2 OR age > $3)", Optional: []string{"name"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+
+	// a top level OR is rejected, dropping a predicate would change what the others filter
+	pq = newStubQuery("where_tenant_id", "where_name", "where_email")
+	pq.QueryType = parser.QueryTypeSelect
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+	query = &config.Query{Name: "search", Sql: "SELECT * FROM users WHERE tenant_id = $1 AND name = $2 OR email = $3", Optional: []string{"name"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "optional arg where_name is in a WHERE with a top level OR")
+
+	pq = newStubQuery("where_tenant_id", "where_name", "where_age")
+	pq.QueryType = parser.QueryTypeSelect
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+	query = &config.Query{Name: "search", Sql: "SELECT * FROM users WHERE tenant_id = $1 AND name = $2 AND age > $3", Optional: []string{"email"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "optional arg email matches no arg")
+
+	pq = newStubQuery("set_name", "where_id")
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+	query = &config.Query{Name: "update", Sql: "UPDATE users SET name = $1 WHERE id = $2", Optional: []string{"set_name"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "optional arg set_name is not in a top level WHERE predicate")
+}
+
+func TestSetDataQueryUpdateNullIgnore(t *testing.T) {
+	genQueries := &GenQueries{}
+	genQueries.Init(newStubConf(), &stubParser{pq: newStubQuery("set_name", "set_age", "where_id")})
+
+	query := &config.Query{Name: "patch", Sql: "UPDATE users SET name = $1, age = $2 WHERE id = $3", UpdateNullIgnore: true}
+	parsed, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.True(t, parsed.UpdateNullIgnore)
+	require.Equal(t, []bool{true, true, false}, []bool{parsed.Arg[0].IsOptional, parsed.Arg[1].IsOptional, parsed.Arg[2].IsOptional})
+	require.Equal(t, []string{"*string", "*string", "string"}, []string{parsed.Arg[0].GoType, parsed.Arg[1].GoType, parsed.Arg[2].GoType})
+
+	// optional where args are dropped after the set
+	genQueries.Init(newStubConf(), &stubParser{pq: newStubQuery("set_name", "where_id", "where_name")})
+	query = &config.Query{Name: "patch", Sql: "UPDATE users SET name = $1 WHERE id = $2 AND name = $3", UpdateNullIgnore: true, Optional: []string{"where_name"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+
+	pq := newStubQuery("where_id")
+	pq.QueryType = parser.QueryTypeDelete
+	genQueries.Init(newStubConf(), &stubParser{pq: pq})
+	query = &config.Query{Name: "delete", Sql: "DELETE FROM users WHERE id = $1", UpdateNullIgnore: true}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "only valid for update")
+}
+
+// recordParser records the sql it parses.
+type recordParser struct {
+	stubParser
+	sql string
+}
+
+func (t *recordParser) Parse(sql string) (*parser.ParsedQuery, error) {
+	t.sql = sql
+	return t.stubParser.Parse(sql)
+}
+
+func TestSetDataQueryTpl(t *testing.T) {
+	pq := newStubQuery("name")
+	pq.QueryType = parser.QueryTypeSelect
+	psr := &recordParser{stubParser: stubParser{pq: pq}}
+	genQueries := &GenQueries{}
+	genQueries.Init(&config.Config{}, psr)
+
+	query := &config.Query{
+		Name:      "search",
+		Sql:       "SELECT * FROM users_#shard/2024# WHERE name LIKE :name AND memo <> '#x#' ORDER BY #order/id#",
+		TplValues: map[string][]string{"order": {"id", "name"}},
+	}
+	parsed, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.Equal(t, "SELECT * FROM users_2024 WHERE name LIKE :name AND memo <> '#x#' ORDER BY id", psr.sql)
+	require.Equal(t, "SELECT * FROM users_%s WHERE name LIKE ? AND memo <> '#x#' ORDER BY %s", parsed.Query)
+	require.Len(t, parsed.Tpl, 2)
+	require.Equal(t, "shard", parsed.Tpl[0].Name)
+	require.Empty(t, parsed.Tpl[0].Allowed)
+	require.Equal(t, []string{"id", "name"}, parsed.Tpl[1].Allowed)
+
+	query = &config.Query{Name: "search", Sql: "SELECT * FROM users WHERE flags # 4 = 0 AND #1#"}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "is not a tpl name")
+
+	query = &config.Query{Name: "search", Sql: "SELECT * FROM users_#shard#", TplValues: map[string][]string{"order": {"id"}}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "tpl_values order matches no tpl")
+}
+
+func TestSetDataQueryOrderBy(t *testing.T) {
+	conf := &config.Config{}
+	users := schema.NewTable("users").AddColumns(schema.NewStringColumn("name", "text"), schema.NewTimeColumn("created_at", "timestamp"))
+	orders := schema.NewTable("orders").AddColumns(schema.NewIntColumn("amount", "bigint"))
+	conf.Schema.Init(atlas.DbTypeMySQL, schema.New("public").AddTables(users, orders))
+	pq := newStubQuery()
+	pq.QueryType = parser.QueryTypeSelect
+	pq.Ret = append(pq.Ret, parser.NewField("name", "string"))
+	genQueries := &GenQueries{}
+	genQueries.Init(conf, &stubParser{pq: pq})
+
+	query := &config.Query{Name: "list", Sql: "SELECT name FROM users u JOIN orders o ON o.user = u.id", OrderBy: []string{"created_at", "orders.amount"}}
+	parsed, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.Equal(t, []string{"created_at", "orders.amount"}, parsed.OrderBy)
+
+	// a column without table is a column of the group table
+	query = &config.Query{Name: "list", Sql: "SELECT name FROM users", OrderBy: []string{"amount"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "order_by column amount is not in the schema")
+
+	query = &config.Query{Name: "list", Sql: "SELECT name FROM users", OrderBy: []string{"name DESC"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "not in the schema")
+
+	pq.QueryType = parser.QueryTypeDelete
+	query = &config.Query{Name: "delete", Sql: "DELETE FROM users", OrderBy: []string{"name"}}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "only valid for select")
+}
+
+func TestSetDataQueryPaginate(t *testing.T) {
+	conf := &config.Config{}
+	users := schema.NewTable("users").AddColumns(schema.NewIntColumn("id", "bigint"), schema.NewStringColumn("name", "text"), schema.NewNullStringColumn("email", "text"))
+	users.SetPrimaryKey(schema.NewPrimaryKey(users.Columns[0]))
+	conf.Schema.Init(atlas.DbTypePostgre, schema.New("public").AddTables(users))
+	newQuery := func() *parser.ParsedQuery {
+		pq := newStubQuery()
+		pq.QueryType = parser.QueryTypeSelect
+		pq.Ret = append(pq.Ret, parser.NewField("id", "int64"), parser.NewField("name", "string"), parser.NewField("email", "*string"))
+		return pq
+	}
+	genQueries := &GenQueries{}
+
+	// the primary key when the select has no ORDER BY
+	genQueries.Init(conf, &stubParser{pq: newQuery()})
+	query := &config.Query{Name: "list", Sql: "SELECT id, name FROM users", Paginate: true}
+	parsed, err := genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.Equal(t, &parser.Page{Columns: []string{"id"}, Fields: []string{"id"}, Numbered: true}, parsed.Page)
+
+	genQueries.Init(conf, &stubParser{pq: newQuery()})
+	query = &config.Query{Name: "list", Sql: "SELECT id, name FROM users u ORDER BY u.name DESC, u.id DESC", Paginate: true}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.Equal(t, &parser.Page{Columns: []string{"u.name", "u.id"}, Fields: []string{"name", "id"}, Desc: true, Numbered: true}, parsed.Page)
+
+	// the primary key breaks the ties of the ORDER BY
+	genQueries.Init(conf, &stubParser{pq: newQuery()})
+	query = &config.Query{Name: "list", Sql: "SELECT id, name FROM users ORDER BY name", Paginate: true}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.NotNil(t, parsed)
+	require.Equal(t, &parser.Page{Columns: []string{"name", "id"}, Fields: []string{"name", "id"}, Numbered: true}, parsed.Page)
+
+	for _, test := range []struct {
+		sql      string
+		orderBy  []string
+		expected string
+	}{
+		{"SELECT id, name FROM users ORDER BY created_at", nil, "paginate key column created_at is not returned by the select"},
+		{"SELECT id, name FROM users LIMIT 10", nil, "paginated select can not have LIMIT"},
+		{"SELECT id, name FROM users ORDER BY email", nil, "paginate key column email can not be nullable"},
+		{"SELECT id, name FROM users", []string{"name"}, "paginate option can not be combined with order_by"},
+	} {
+		genQueries.Init(conf, &stubParser{pq: newQuery()})
+		query = &config.Query{Name: "list", Sql: test.sql, OrderBy: test.orderBy, Paginate: true}
+		parsed, err = genQueries.SetDataQuery("users", query)
+		require.NoError(t, err)
+		require.Nil(t, parsed)
+		require.Contains(t, query.ErrQuery, test.expected)
+	}
+
+	pq := newQuery()
+	pq.QueryType = parser.QueryTypeDelete
+	genQueries.Init(conf, &stubParser{pq: pq})
+	query = &config.Query{Name: "delete", Sql: "DELETE FROM users", Paginate: true}
+	parsed, err = genQueries.SetDataQuery("users", query)
+	require.NoError(t, err)
+	require.Nil(t, parsed)
+	require.Contains(t, query.ErrQuery, "only valid for select")
+}
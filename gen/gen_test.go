@@ -0,0 +1,92 @@
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/gosuda/ornn/atlas"
+	"github.com/gosuda/ornn/config"
+	"github.com/gosuda/ornn/parser"
+	"github.com/gosuda/ornn/parser/parser_mysql"
+	"github.com/gosuda/ornn/parser/parser_postgres"
+	"github.com/gosuda/ornn/parser/parser_sqlite"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenVet generates the code of every query option through the parsers and go vets it,
+// in a module of its own which requires this one from the working tree.
+func TestGenVet(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go is not in PATH")
+	}
+	dir := t.TempDir()
+	root, err := filepath.Abs("..")
+	require.NoError(t, err)
+	goMod, err := os.ReadFile(filepath.Join(root, "go.mod"))
+	require.NoError(t, err)
+	goSum, err := os.ReadFile(filepath.Join(root, "go.sum"))
+	require.NoError(t, err)
+
+	// the requirements of this module keep the versions and the go.sum of its dependencies
+	goMod = bytes.Replace(goMod, []byte("module github.com/gosuda/ornn"), []byte("module vet"), 1)
+	goMod = append(goMod, fmt.Sprintf("\nrequire github.com/gosuda/ornn v0.0.0\n\nreplace github.com/gosuda/ornn => %s\n", root)...)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), goMod, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), goSum, 0o644))
+
+	for _, test := range []struct {
+		dbType atlas.DbType
+		new    func(sch *config.Schema) parser.Parser
+		types  [3]string // raw types of the bigint, text and timestamp columns
+	}{
+		{atlas.DbTypeMySQL, parser_mysql.New, [3]string{"bigint", "varchar(255)", "datetime"}},
+		{atlas.DbTypePostgre, parser_postgres.New, [3]string{"bigint", "text", "timestamp with time zone"}},
+		{atlas.DbTypeSQLite, parser_sqlite.New, [3]string{"integer", "text", "datetime"}},
+	} {
+		users := &schema.Table{Name: "users"}
+		users.Columns = []*schema.Column{
+			{Name: "id", Type: &schema.ColumnType{Raw: test.types[0], Type: &schema.IntegerType{T: test.types[0]}}},
+			{Name: "tenant_id", Type: &schema.ColumnType{Raw: test.types[0], Type: &schema.IntegerType{T: test.types[0]}}},
+			{Name: "name", Type: &schema.ColumnType{Raw: test.types[1], Type: &schema.StringType{T: test.types[1]}}},
+			{Name: "email", Type: &schema.ColumnType{Raw: test.types[1], Type: &schema.StringType{T: test.types[1]}, Null: true}},
+			{Name: "created_at", Type: &schema.ColumnType{Raw: test.types[2], Type: &schema.TimeType{T: test.types[2]}}},
+		}
+		users.PrimaryKey = &schema.Index{Parts: []*schema.IndexPart{{C: users.Columns[0]}}}
+
+		pkg := atlas.DbTypeStr[test.dbType]
+		conf := &config.Config{}
+		err := conf.Init(test.dbType, &schema.Schema{Tables: []*schema.Table{users}}, dir, pkg+".go", pkg, "Gen")
+		require.NoError(t, err)
+		for _, query := range []*config.Query{
+			{Name: "search", Sql: "SELECT id, name, email FROM users WHERE tenant_id = :tenant_id AND name = :name", Optional: []string{"name"}},
+			{Name: "listByIds", Sql: "SELECT id, name FROM users WHERE id IN (sqlc.slice(ids))"},
+			{Name: "list", Sql: "SELECT id, name, created_at FROM users WHERE tenant_id = :tenant_id", Paginate: true},
+			{Name: "recent", Sql: "SELECT id, name, created_at FROM users ORDER BY created_at DESC", Paginate: true},
+			{Name: "sorted", Sql: "SELECT id, name FROM users WHERE tenant_id = :tenant_id", OrderBy: []string{"name", "created_at"}},
+			{Name: "patch", Sql: "UPDATE users SET name = :name, email = :email WHERE id = :id", UpdateNullIgnore: true},
+			{Name: "deleteShard", Sql: "DELETE FROM #table/users# WHERE id = :id"},
+			{Name: "insertBulk", Sql: "INSERT INTO users (tenant_id, name, created_at) VALUES (:tenant_id, :name, :created_at)", Bulk: true},
+			{Name: "rename", Sql: "UPDATE users SET name = :name WHERE id = :id; SELECT id, name FROM users WHERE id = :id"},
+		} {
+			conf.Queries.AddQuery(users.Name, query)
+		}
+		if test.dbType != atlas.DbTypeMySQL {
+			conf.Queries.AddQuery(users.Name, &config.Query{Name: "insertReturning", Sql: "INSERT INTO users (tenant_id, name, created_at) VALUES (:tenant_id, :name, :created_at) RETURNING id"})
+		}
+
+		code, err := (&Gen{}).Gen(conf, test.new(&conf.Schema))
+		require.NoError(t, err, test.dbType)
+		pkgDir := filepath.Join(dir, pkg)
+		require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(pkgDir, pkg+".go"), []byte(code), 0o644))
+	}
+
+	cmd := exec.Command("go", "vet", "./...")
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
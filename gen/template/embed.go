@@ -14,5 +14,20 @@ var UpdateTmpl string
 //go:embed delete.template
 var DeleteTmpl string
 
+//go:embed returning.template
+var ReturningTmpl string
+
+//go:embed bulk_insert.template
+var BulkInsertTmpl string
+
+//go:embed multi.template
+var MultiTmpl string
+
+//go:embed copy_from.template
+var CopyFromTmpl string
+
+//go:embed page.template
+var PageTmpl string
+
 //go:embed use_case.template
 var UseCaseTmpl string
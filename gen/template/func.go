@@ -2,12 +2,11 @@ package template
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
-
-	"github.com/gosuda/ornn/gen/util"
 )
 
-func Select(args []string, tpls []string, query string, selectSingle bool, structName string, instanceName string, retName, retItemName, retItemType string) string {
+func Select(args []string, tpls []string, query string, orderBy, optionalSet, optionalWhere, expandSlice bool, selectSingle bool, structName string, instanceName string, retName, retItemName, retItemType string, scanDests []string) string {
 	var bodyRetDeclare, bodyRetSet string
 	if selectSingle == true {
 		bodyRetSet = fmt.Sprintf("%s = scan\n\tbreak", retItemName)
@@ -19,56 +18,146 @@ func Select(args []string, tpls []string, query string, selectSingle bool, struc
 		"arg":      genQuery_body_setArgs(args),
 		"query":    query,
 		"tpl":      genQuery_body_arg(tpls),
+		"expand":   genQuery_body_orderBy(orderBy, "nil") + genQuery_body_optionalSet(optionalSet, "nil") + genQuery_body_optionalWhere(optionalWhere, "nil") + genQuery_body_expandSlice(expandSlice, "nil"),
 		"struct":   structName,
 		"instance": instanceName,
 		"body":     bodyRetDeclare,
 		"scan":     retName,
+		"fields":   genQuery_body_scanDests(scanDests),
 		"retSet":   bodyRetSet,
 		"ret":      retItemName,
 	})
 }
 
-func Insert(args []string, tpls []string, query string, insertMulti bool, structName, instanceName string) string {
-	var multiInsert, genArgs string
-	if insertMulti == true { // multi insert
-		queryVal := util.ExportInsertQueryValues(query)
-		query = strings.TrimSuffix(query, ";")
-		query += "%s"
-		genArgs = genQuery_body_multiInsertProc(args)
-		multiInsert = genQuery_body_multiInsert(queryVal)
-	} else { // insert
-		genArgs = genQuery_body_setArgs(args)
+// Page is a select limited to a page of rows after the cursor, keyset is the db.Keyset literal of the select
+// and keyFields the fields of the last row the cursor of the next page is built from, and the cursor decoded into
+func Page(args []string, tpls []string, query string, optionalWhere, expandSlice bool, keyset string, keyFields []string, structName, instanceName string, retName, retItemName, retItemType string, scanDests []string) string {
+	key, after := make([]string, len(keyFields)), make([]string, len(keyFields))
+	for i, field := range keyFields {
+		key[i] = "last." + field
+		after[i] = "&after." + field
 	}
+	return parseTemplate(PageTmpl, map[string]any{
+		"arg":      genQuery_body_setArgs(args),
+		"query":    query,
+		"tpl":      genQuery_body_arg(tpls),
+		"expand":   genQuery_body_optionalWhere(optionalWhere, `nil, ""`) + genQuery_body_expandSlice(expandSlice, `nil, ""`),
+		"keyset":   keyset,
+		"struct":   structName,
+		"instance": instanceName,
+		"scan":     retName,
+		"fields":   genQuery_body_scanDests(scanDests),
+		"ret":      retItemName,
+		"retType":  retItemType,
+		"key":      strings.Join(key, ", "),
+		"after":    strings.Join(after, ", "),
+	})
+}
+
+func Insert(args []string, tpls []string, query string, expandSlice bool, structName, instanceName string) string {
 	return parseTemplate(InsertTmpl, map[string]any{
-		"arg":      genArgs,
+		"arg":      genQuery_body_setArgs(args),
 		"query":    query,
 		"tpl":      genQuery_body_arg(tpls),
-		"multi":    multiInsert,
+		"expand":   genQuery_body_expandSlice(expandSlice, "0"),
+		"struct":   structName,
+		"instance": instanceName,
+	})
+}
+
+// BulkInsert executes a single-row insert for every row, rowArgs are the row fields bound to each placeholder
+func BulkInsert(rowName string, rowArgs []string, tpls []string, query string, maxArgs string, structName, instanceName string) string {
+	return parseTemplate(BulkInsertTmpl, map[string]any{
+		"row":      rowName,
+		"count":    len(rowArgs),
+		"rowArgs":  strings.ReplaceAll(genQuery_body_arg(rowArgs), "\n", "\n\t"),
+		"query":    strings.TrimSuffix(strings.TrimSpace(query), ";"),
+		"tpl":      genQuery_body_arg(tpls),
+		"maxArgs":  maxArgs,
+		"struct":   structName,
+		"instance": instanceName,
+	})
+}
+
+// Multi runs the statement calls in a transaction, the rets of the last call are returned
+func Multi(calls []string, rets []string, groupName, structName, instanceName string) string {
+	var body strings.Builder
+	for i, call := range calls {
+		if i < len(calls)-1 {
+			fmt.Fprintf(&body, "\tif _, err = tx.%s; err != nil {\n\t\treturn err\n\t}\n", call)
+			continue
+		}
+		fmt.Fprintf(&body, "\t%s = tx.%s\n\treturn err", strings.Join(rets, ", "), call)
+	}
+	return parseTemplate(MultiTmpl, map[string]any{
+		"group":    groupName,
+		"body":     body.String(),
+		"ret":      strings.Join(rets, ", "),
 		"struct":   structName,
 		"instance": instanceName,
 	})
 }
 
-func Update(args []string, tpls []string, query string, structName, instanceName string) string {
+func CopyFrom(tableName string, columns []string, rowArgs []string, structName, instanceName string) string {
+	quoted := make([]string, len(columns))
+	for i, col := range columns {
+		quoted[i] = strconv.Quote(col)
+	}
+	return parseTemplate(CopyFromTmpl, map[string]any{
+		"table":    strconv.Quote(tableName),
+		"columns":  strings.Join(quoted, ", "),
+		"rowArgs":  strings.ReplaceAll(genQuery_body_arg(rowArgs), "\n", "\n\t"),
+		"struct":   structName,
+		"instance": instanceName,
+	})
+}
+
+func Update(args []string, tpls []string, query string, optionalSet, optionalWhere, expandSlice bool, structName, instanceName string) string {
 	return parseTemplate(UpdateTmpl, map[string]any{
 		"query":    query,
 		"tpl":      genQuery_body_arg(tpls),
 		"arg":      genQuery_body_setArgs(args),
+		"expand":   genQuery_body_optionalSet(optionalSet, "0") + genQuery_body_optionalWhere(optionalWhere, "0") + genQuery_body_expandSlice(expandSlice, "0"),
 		"struct":   structName,
 		"instance": instanceName,
 	})
 }
 
-func Delete(args []string, query string, tpls []string, structName, instanceName string) string {
+func Delete(args []string, query string, optionalWhere, expandSlice bool, tpls []string, structName, instanceName string) string {
 	return parseTemplate(DeleteTmpl, map[string]any{
 		"arg":      genQuery_body_setArgs(args),
 		"query":    query,
 		"tpl":      genQuery_body_arg(tpls),
+		"expand":   genQuery_body_optionalWhere(optionalWhere, "0") + genQuery_body_expandSlice(expandSlice, "0"),
 		"struct":   structName,
 		"instance": instanceName,
 	})
 }
 
+// Returning is an insert/update/delete with a RETURNING clause that returns a single row
+func Returning(args []string, tpls []string, query string, expandSlice bool, structName, instanceName string, retName string, scanDests []string) string {
+	return parseTemplate(ReturningTmpl, map[string]any{
+		"arg":      genQuery_body_setArgs(args),
+		"query":    query,
+		"tpl":      genQuery_body_arg(tpls),
+		"expand":   genQuery_body_expandSlice(expandSlice, "nil"),
+		"struct":   structName,
+		"instance": instanceName,
+		"scan":     retName,
+		"fields":   genQuery_body_scanDests(scanDests),
+	})
+}
+
+// CheckTpl checks the value of a tpl arg before it is formatted into the sql, returning zero and the error
+// for a value not allowed, see db.CheckTpl.
+func CheckTpl(name, arg string, allowed []string, zero string) string {
+	args := []string{strconv.Quote(name), arg}
+	for _, value := range allowed {
+		args = append(args, strconv.Quote(value))
+	}
+	return fmt.Sprintf("if err = CheckTpl(%s); err != nil {\n\treturn %s, err\n}\n", strings.Join(args, ", "), zero)
+}
+
 func UseCase(packageName, className string) string {
 	return parseTemplate(UseCaseTmpl, map[string]any{
 		"package": packageName,
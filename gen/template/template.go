@@ -28,60 +28,55 @@ func genQuery_body_setArgs(args []string) string {
 	return fmt.Sprintf("args := []any{%s}\n", items)
 }
 
-// genQuery_body_arg formats args as a comma-separated list with indentation
-func genQuery_body_arg(args []string) string {
-	if len(args) == 0 {
+// genQuery_body_expandSlice generates code expanding slice arg placeholders, returning zero and the error
+// for an empty slice, see db.ExpandSlice
+func genQuery_body_expandSlice(expandSlice bool, zero string) string {
+	if expandSlice == false {
 		return ""
 	}
-	var b strings.Builder
-	for _, arg := range args {
-		fmt.Fprintf(&b, "\n\t%s,", arg)
-	}
-	return b.String()
+	return fmt.Sprintf("sql, args, err = ExpandSlice(sql, args)\nif err != nil {\n\treturn %s, err\n}\n", zero)
 }
 
-// genQuery_body_multiInsertProc generates code for multi-insert procedure
-func genQuery_body_multiInsertProc(args []string) string {
-	if len(args) == 0 {
+// genQuery_body_orderBy generates code setting the ORDER BY to the orderBy and direction args, returning zero and the error
+// for an invalid column, see db.OrderBy
+func genQuery_body_orderBy(orderBy bool, zero string) string {
+	if orderBy == false {
 		return ""
 	}
+	return fmt.Sprintf("sql, err = OrderBy(sql, orderBy, direction)\nif err != nil {\n\treturn %s, err\n}\n", zero)
+}
 
-	// checkLen: argLen != len(arg1) || argLen != len(arg2) ...
-	checkConds := make([]string, len(args))
-	for i, arg := range args {
-		checkConds[i] = fmt.Sprintf("argLen != len(%s)", arg)
+// genQuery_body_optionalSet generates code dropping the SET assignments of nil args, returning zero and the error
+// when none is left, see db.OptionalSet
+func genQuery_body_optionalSet(optionalSet bool, zero string) string {
+	if optionalSet == false {
+		return ""
 	}
-	checkLen := strings.Join(checkConds, " || ")
+	return fmt.Sprintf("sql, args, err = OptionalSet(sql, args)\nif err != nil {\n\treturn %s, err\n}\n", zero)
+}
 
-	// append part: arg1[i], arg2[i], ...
-	appendArgs := make([]string, len(args))
-	for i, arg := range args {
-		appendArgs[i] = fmt.Sprintf("%s[i]", arg)
+// genQuery_body_optionalWhere generates code dropping the WHERE predicates of nil optional args, returning zero and the error
+// for a nil arg in a WHERE with a top level OR, see db.OptionalWhere
+func genQuery_body_optionalWhere(optionalWhere bool, zero string) string {
+	if optionalWhere == false {
+		return ""
 	}
-
-	return fmt.Sprintf(`argLen := len(%s)
-if argLen == 0 {
-	return 0, fmt.Errorf("arg len is zero")
-}
-if %s {
-	return 0, fmt.Errorf("arg len is not same")
+	return fmt.Sprintf("sql, args, err = OptionalWhere(sql, args)\nif err != nil {\n\treturn %s, err\n}\n", zero)
 }
 
-args := make([]any, 0, argLen*%d)
-for i := 0; i < argLen; i++ {
-	args = append(args, I_to_arri(
-		%s,
-	)...)
-}
-`,
-		args[0],
-		checkLen,
-		len(args),
-		strings.Join(appendArgs, ",\n\t\t"),
-	)
+// genQuery_body_scanDests joins the scan destinations (&scan.Field, ...) of the returned struct
+func genQuery_body_scanDests(dests []string) string {
+	return strings.Join(dests, ", ")
 }
 
-// genQuery_body_multiInsert generates code snippet for multi insert SQL
-func genQuery_body_multiInsert(query string) string {
-	return fmt.Sprintf("\n\tstrings.Repeat(\", (%s)\", argLen-1),", query)
+// genQuery_body_arg formats args as a comma-separated list with indentation
+func genQuery_body_arg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, arg := range args {
+		fmt.Fprintf(&b, "\n\t%s,", arg)
+	}
+	return b.String()
 }
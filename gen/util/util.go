@@ -55,7 +55,7 @@ func SplitByDelimiter(sql, delimiter string) (before, after string) {
 // ExportBetweenDelimiter extracts all values between delimiters and checks for duplicates.
 func ExportBetweenDelimiter(input, delimiter string) ([]string, error) {
 	cleaned := ClearInQuot(input)
-	var outputs []string
+	outputs := []string{}
 	var buf strings.Builder
 	in := false
 
@@ -118,11 +118,29 @@ func ClearDelimiter(input, delimiter string) string {
 
 // ReplaceInDelimiter keeps only the last segment after a splitter inside the delimiter.
 func ReplaceInDelimiter(input, delimiter, splitter string) string {
+	out, _ := replaceInDelimiter(input, delimiter, splitter)
+	return out
+}
+
+// InDelimiterOffset maps a byte offset in the output of ReplaceInDelimiter back to the input,
+// an offset in a replaced segment maps to its opening delimiter.
+func InDelimiterOffset(input, delimiter, splitter string, offset int) int {
+	if offset < 0 {
+		return offset
+	}
+	_, origin := replaceInDelimiter(input, delimiter, splitter)
+	return origin[min(offset, len(origin)-1)]
+}
+
+// replaceInDelimiter is ReplaceInDelimiter, origin[i] is the offset in input of the byte i of the output
+// (and origin[len(output)] is len(input)).
+func replaceInDelimiter(input, delimiter, splitter string) (string, []int) {
 	// e.g., xxxx#AAAA#xxxx -> xxxxAAAAxxxx
 	//       xxxx#AAAA/BBBB#xxxx -> xxxxBBBBxxxx
 	cleaned := ClearInQuot(input)
 	var out, buf strings.Builder
-	in := false
+	origin := make([]int, 0, len(input)+1)
+	in, start := false, 0
 
 	for i := 0; i < len(input); i++ {
 		ch, qch := input[i], cleaned[i]
@@ -130,14 +148,19 @@ func ReplaceInDelimiter(input, delimiter, splitter string) string {
 			in = !in
 			if in {
 				buf.Reset() // enter
+				start = i
 			} else {
 				out.WriteString(buf.String()) // exit
+				for range buf.Len() {
+					origin = append(origin, start)
+				}
 			}
 			continue
 		}
 
 		if !in {
 			out.WriteByte(ch)
+			origin = append(origin, i)
 		} else {
 			if string(qch) == splitter {
 				buf.Reset() // keep only after splitter
@@ -146,7 +169,7 @@ func ReplaceInDelimiter(input, delimiter, splitter string) string {
 			}
 		}
 	}
-	return out.String()
+	return out.String(), append(origin, len(input))
 }
 
 // ExportInsertQueryValues extracts the part inside the first VALUES (...) in an INSERT statement.
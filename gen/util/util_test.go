@@ -158,6 +158,15 @@ func TestReplaceInDelimiter(t *testing.T) {
 	}
 }
 
+func TestInDelimiterOffset(t *testing.T) {
+	input := "SELECT * FROM #table/users# ORDER BY BY id"
+	output := ReplaceInDelimiter(input, "#", "/")
+	require.Equal(t, strings.LastIndex(input, "BY"), InDelimiterOffset(input, "#", "/", strings.LastIndex(output, "BY")))
+	require.Equal(t, strings.Index(input, "#"), InDelimiterOffset(input, "#", "/", strings.Index(output, "users")+2))
+	require.Equal(t, len(input), InDelimiterOffset(input, "#", "/", len(output)))
+	require.Equal(t, -1, InDelimiterOffset(input, "#", "/", -1))
+}
+
 func TestExportInsertQueryValues(t *testing.T) {
 	for _, test := range []struct {
 		input  string
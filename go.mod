@@ -20,7 +20,6 @@ require (
 	github.com/benbjohnson/clock v1.3.0 // indirect
 	github.com/biogo/store v0.0.0-20201120204734-aad293a2328f // indirect
 	github.com/cockroachdb/apd/v3 v3.1.0 // indirect
-	github.com/cockroachdb/errors v1.9.0 // indirect
 	github.com/cockroachdb/logtags v0.0.0-20211118104740-dabe8e521a4f // indirect
 	github.com/cockroachdb/redact v1.1.3 // indirect
 	github.com/dustin/go-humanize v1.0.0 // indirect
@@ -76,6 +75,7 @@ require (
 	ariga.io/atlas v0.9.0
 	github.com/agext/levenshtein v1.2.3 // indirect
 	github.com/cockroachdb/cockroachdb-parser v0.0.0-20221207165326-ea0ac1a4778b
+	github.com/cockroachdb/errors v1.9.0
 	github.com/google/go-cmp v0.5.9 // indirect
 	github.com/mitchellh/go-wordwrap v1.0.1 // indirect
 	github.com/zclconf/go-cty v1.12.1 // indirect
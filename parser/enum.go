@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/gosuda/ornn/db"
+)
+
+// Enum is a database enum generated as a named go string type with a constant per value.
+type Enum struct {
+	GoType string
+	Values []string
+}
+
+// EnumOf returns the enum of the column type, or nil if it is not an enum.
+// Postgres enums are named after the type (member_role → MemberRole), MySQL enums are declared
+// per column and named after the table and column (org_members.role → OrgMembersRole).
+func EnumOf(sch *schema.Schema, colType *schema.ColumnType) *Enum {
+	if colType == nil {
+		return nil
+	}
+	enum, ok := colType.Type.(*schema.EnumType)
+	if ok == false || len(enum.Values) == 0 {
+		return nil
+	}
+
+	name := enum.T
+	if i := strings.LastIndexByte(name, '.'); i != -1 {
+		name = name[i+1:]
+	}
+	if name == "" || strings.EqualFold(name, "enum") {
+		name = ""
+		if sch != nil {
+			for _, tbl := range sch.Tables {
+				for _, col := range tbl.Columns {
+					if col.Type == colType {
+						name = tbl.Name + "_" + col.Name
+					}
+				}
+			}
+		}
+		if name == "" {
+			return nil
+		}
+	}
+	return &Enum{
+		GoType: goName(strings.Trim(name, `"`)),
+		Values: enum.Values,
+	}
+}
+
+// Enums returns the enums of every column of the schema in table and column order, each once.
+func Enums(sch *schema.Schema) (enums []*Enum) {
+	if sch == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, tbl := range sch.Tables {
+		for _, col := range tbl.Columns {
+			enum := EnumOf(sch, col.Type)
+			if enum == nil || seen[enum.GoType] == true {
+				continue
+			}
+			seen[enum.GoType] = true
+			enums = append(enums, enum)
+		}
+	}
+	return enums
+}
+
+// ConstName returns the go constant name of an enum value, e.g. MemberRole + "read-only" → MemberRoleReadOnly.
+func (t *Enum) ConstName(value string) string {
+	name := goName(value)
+	if name == "" {
+		name = "Empty"
+	}
+	return t.GoType + name
+}
+
+// goName converts a snake, kebab or space separated name to an exported go name.
+func goName(s string) string {
+	var out strings.Builder
+	upper := true
+	for _, ch := range s {
+		switch {
+		case ch < 128 && db.IsIdentChar(byte(ch)) == false, ch == '_':
+			upper = true
+			continue
+		case upper:
+			out.WriteString(strings.ToUpper(string(ch)))
+		default:
+			out.WriteRune(ch)
+		}
+		upper = false
+	}
+	return out.String()
+}
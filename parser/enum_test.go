@@ -0,0 +1,41 @@
+package parser
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnums(t *testing.T) {
+	role := &schema.ColumnType{Type: &schema.EnumType{T: "enum", Values: []string{"owner", "admin"}}}
+	status := &schema.ColumnType{Type: &schema.EnumType{T: "public.task_status", Values: []string{"todo", "in-progress", "2fa"}}}
+
+	members := &schema.Table{Name: "org_members"}
+	members.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int"}}},
+		{Name: "role", Type: role},
+	}
+	tasks := &schema.Table{Name: "tasks"}
+	tasks.Columns = []*schema.Column{
+		{Name: "status", Type: status},
+		{Name: "prev_status", Type: status},
+	}
+	sch := &schema.Schema{}
+	sch.AddTables(members, tasks)
+
+	enum := EnumOf(sch, role)
+	require.Equal(t, &Enum{GoType: "OrgMembersRole", Values: []string{"owner", "admin"}}, enum)
+	require.Nil(t, EnumOf(sch, members.Columns[0].Type))
+
+	enum = EnumOf(sch, status)
+	require.Equal(t, "TaskStatus", enum.GoType)
+	require.Equal(t, "TaskStatusInProgress", enum.ConstName("in-progress"))
+	require.Equal(t, "TaskStatus2fa", enum.ConstName("2fa"))
+	require.Equal(t, "TaskStatusEmpty", enum.ConstName(""))
+
+	enums := Enums(sch)
+	require.Len(t, enums, 2)
+	require.Equal(t, "OrgMembersRole", enums[0].GoType)
+	require.Equal(t, "TaskStatus", enums[1].GoType)
+}
@@ -0,0 +1,65 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gosuda/ornn/db"
+)
+
+// Error is a parser error located in the sql, either by byte offset or by the identifier it is about.
+type Error struct {
+	Msg    string
+	Offset int    // byte offset in the sql, -1 if unknown
+	Ident  string // identifier the error is about, searched in the sql when Offset is unknown
+}
+
+func (t *Error) Error() string {
+	return t.Msg
+}
+
+// NewSyntaxError wraps a syntax error of the underlying sql parser found at offset (-1 if unknown).
+func NewSyntaxError(err error, offset int) error {
+	return &Error{
+		Msg:    "parser error | " + err.Error(),
+		Offset: offset,
+	}
+}
+
+// ErrorOffset returns the byte offset in sql the error points at, or -1.
+func ErrorOffset(sql string, err error) int {
+	var perr *Error
+	if errors.As(err, &perr) != true {
+		return -1
+	}
+	if perr.Offset >= 0 || perr.Ident == "" {
+		return min(perr.Offset, len(sql))
+	}
+
+	// a qualified name (alias.col) may be written with spaces or quotes, fall back to the last part
+	if idx := IndexIdent(sql, perr.Ident); idx != -1 {
+		return idx
+	}
+	if i := strings.LastIndexByte(perr.Ident, '.'); i != -1 {
+		return IndexIdent(sql, perr.Ident[i+1:])
+	}
+	return -1
+}
+
+// IndexIdent returns the index of the first identifier ident in sql outside quoted strings and comments, or -1.
+// Quoted identifiers ("ident", `ident`) match too.
+func IndexIdent(sql, ident string) int {
+	for i := 0; i < len(sql); i++ {
+		if end := db.SkipQuoted(sql, i); end > i {
+			if sql[i] != '\'' && end-i == len(ident)+2 && strings.EqualFold(sql[i+1:end-1], ident) {
+				return i
+			}
+			i = end - 1
+			continue
+		}
+		if (i == 0 || !db.IsIdentChar(sql[i-1])) && matchWords(sql[i:], []string{ident}) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,23 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexIdent(t *testing.T) {
+	require.Equal(t, 26, IndexIdent("SELECT * FROM users WHERE name = 'name'", "name"))
+	require.Equal(t, 14, IndexIdent("SELECT 'id' , \"id\" FROM t", "id"))
+	require.Equal(t, -1, IndexIdent("SELECT username FROM t", "name"))
+}
+
+func TestErrorOffset(t *testing.T) {
+	sql := "SELECT o.amout FROM orders o"
+	require.Equal(t, 7, ErrorOffset(sql, &Error{Offset: -1, Ident: "o.amout"}))
+	require.Equal(t, 9, ErrorOffset(sql, &Error{Offset: -1, Ident: "x.amout"}))
+	require.Equal(t, 3, ErrorOffset(sql, fmt.Errorf("wrapped: %w", &Error{Offset: 3})))
+	require.Equal(t, -1, ErrorOffset(sql, errors.New("plain")))
+}
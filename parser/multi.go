@@ -0,0 +1,103 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gosuda/ornn/db"
+)
+
+// SplitStatements splits sql at the semicolons outside quotes and comments, dropping empty statements.
+func SplitStatements(sql string) (stmts []string) {
+	start := 0
+	for i := 0; i < len(sql); i++ {
+		if end := db.SkipQuoted(sql, i); end > i {
+			i = end - 1
+			continue
+		}
+		if sql[i] == ';' {
+			stmts = appendStatement(stmts, sql[start:i])
+			start = i + 1
+		}
+	}
+	return appendStatement(stmts, sql[start:])
+}
+
+func appendStatement(stmts []string, stmt string) []string {
+	if stmt = strings.TrimSpace(stmt); stmt != "" && strings.TrimSpace(stripComments(stmt)) != "" {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+func stripComments(sql string) string {
+	var out strings.Builder
+	for i := 0; i < len(sql); i++ {
+		if end := db.SkipQuoted(sql, i); end > i {
+			if sql[i] != '-' && sql[i] != '/' {
+				out.WriteString(sql[i:end])
+			}
+			i = end - 1
+			continue
+		}
+		out.WriteByte(sql[i])
+	}
+	return out.String()
+}
+
+// ParseStatements parses every statement of a multi-statement query with psr and merges them into one query,
+// whose args are the args of every statement in order and whose result is the one of the last statement.
+// A named arg used by several statements stays one arg, other args sharing a name get _2, _3 ...
+func ParseStatements(psr Parser, sql string, stmts []string) (*ParsedQuery, error) {
+	parsedQuery := &ParsedQuery{}
+	parsedQuery.Init(sql)
+
+	named := make(map[string]string) // type of named args
+	cursor := 0
+	for i, stmt := range stmts {
+		start := cursor + strings.Index(sql[cursor:], stmt)
+		cursor = start + len(stmt)
+
+		stmtQuery, err := psr.Parse(stmt)
+		if err != nil {
+			// offset 을 전체 sql 기준으로 옮김
+			var perr *Error
+			if errors.As(err, &perr) && perr.Offset >= 0 {
+				perr.Offset += start
+			}
+			return nil, fmt.Errorf("parser error | statement %d: %w", i+1, err)
+		}
+
+		for _, arg := range stmtQuery.Arg {
+			if arg.IsNamed != true {
+				continue
+			}
+			prev, ok := named[arg.Name]
+			switch {
+			case !ok || prev == "any":
+				named[arg.Name] = arg.GoType
+			case arg.GoType != prev && arg.GoType != "any":
+				return nil, fmt.Errorf("parser error | named arg %s is used as both %s and %s", arg.Name, prev, arg.GoType)
+			}
+		}
+		parsedQuery.Placeholder += stmtQuery.Placeholder
+		parsedQuery.Tpl = append(parsedQuery.Tpl, stmtQuery.Tpl...)
+		parsedQuery.Arg = append(parsedQuery.Arg, stmtQuery.Arg...)
+		parsedQuery.Stmts = append(parsedQuery.Stmts, stmtQuery)
+	}
+
+	for _, arg := range parsedQuery.Arg {
+		if arg.IsNamed == true {
+			arg.GoType = named[arg.Name]
+		}
+	}
+
+	parsedQuery.UniqueArgNames()
+
+	last := parsedQuery.Stmts[len(parsedQuery.Stmts)-1]
+	parsedQuery.QueryType = last.QueryType
+	parsedQuery.InsertOne = last.InsertOne
+	parsedQuery.Ret = last.Ret
+	return parsedQuery, nil
+}
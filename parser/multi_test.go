@@ -0,0 +1,16 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitStatements(t *testing.T) {
+	require.Equal(t, []string{"SELECT 1"}, SplitStatements("SELECT 1;"))
+	require.Equal(t,
+		[]string{"UPDATE t SET a = ';'", "SELECT \"x;y\" FROM t -- last;"},
+		SplitStatements("UPDATE t SET a = ';' ;\n SELECT \"x;y\" FROM t -- last;\n;"),
+	)
+	require.Equal(t, []string{"SELECT 1"}, SplitStatements("SELECT 1; -- trailing comment"))
+}
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/gosuda/ornn/db"
+)
+
+// NamedArg is a placeholder RewriteNamedArgs found written as a name (:name, @name,
+// sqlc.arg(name)) or as a slice (sqlc.slice(name), ?...).
+type NamedArg struct {
+	Name    string // empty for a plain ? or ?...
+	IsSlice bool
+}
+
+// generatedNames are the identifiers declared or used by the generated functions, which a named arg would shadow
+var generatedNames = map[string]bool{
+	"t": true, "job": true, "tx": true, "fmt": true,
+	"sql": true, "args": true, "err": true, "ret": true, "scan": true, "exec": true, "row": true, "rows": true, "i": true,
+	"orderBy": true, "direction": true, "cursor": true, "limit": true, "next": true, "keyset": true, "last": true, "after": true,
+	"lastInsertId": true, "rowAffected": true,
+}
+
+// RewriteNamedArgs replaces named placeholders and slice markers with positional ones
+// and returns what is bound to each placeholder.
+//
+// With numbered (postgres) a name is rewritten to the same $n every time it appears,
+// so args[n-1] is bound to $n. Otherwise every occurrence becomes "?" and args holds
+// one entry per "?". A query without any of them is returned as is with nil args.
+func RewriteNamedArgs(sql string, numbered bool) (query string, args []*NamedArg, err error) {
+	query, args, _, err = rewriteNamedArgs(sql, numbered)
+	return query, args, err
+}
+
+// NamedArgsOffset maps a byte offset in the query RewriteNamedArgs returns for sql back to sql,
+// e.g. the offset of a syntax error. An offset in a rewritten placeholder maps to its start.
+func NamedArgsOffset(sql string, numbered bool, offset int) int {
+	if offset < 0 {
+		return offset
+	}
+	_, _, origin, err := rewriteNamedArgs(sql, numbered)
+	if err != nil || origin == nil {
+		return min(offset, len(sql))
+	}
+	return origin[min(offset, len(origin)-1)]
+}
+
+// rewriteNamedArgs is RewriteNamedArgs, origin[i] is the offset in sql of the byte i of the query
+// (and origin[len(query)] is len(sql)), nil if the query is returned as is.
+func rewriteNamedArgs(sql string, numbered bool) (query string, args []*NamedArg, origin []int, err error) {
+	var out strings.Builder
+	out.Grow(len(sql))
+	origin = make([]int, 0, len(sql)+1)
+
+	// write writes s in place of sql[from:], a replacement maps to from
+	write := func(s string, from int, replaced bool) {
+		out.WriteString(s)
+		for n := range len(s) {
+			if replaced == true {
+				origin = append(origin, from)
+			} else {
+				origin = append(origin, from+n)
+			}
+		}
+	}
+
+	index := make(map[string]int)
+	named, positional := 0, 0
+	for i := 0; i < len(sql); {
+		// skip quoted strings, identifiers and comments
+		if end := db.SkipQuoted(sql, i); end > i {
+			write(sql[i:end], i, false)
+			i = end
+			continue
+		}
+
+		arg, end := scanNamedArg(sql, i, numbered)
+		if arg == nil {
+			if isPositionalArg(sql, i, numbered) {
+				positional++
+				args = append(args, &NamedArg{})
+			}
+			write(sql[i:i+1], i, false)
+			i++
+			continue
+		}
+		if token.IsKeyword(arg.Name) {
+			return "", nil, nil, fmt.Errorf("parser error | named arg %s is a reserved go keyword", arg.Name)
+		}
+		if generatedNames[arg.Name] == true {
+			return "", nil, nil, fmt.Errorf("parser error | named arg %s is declared by the generated code", arg.Name)
+		}
+		if arg.Name != "" {
+			named++
+		}
+
+		switch {
+		case !numbered:
+			args = append(args, arg)
+			write("?", i, true)
+		case index[arg.Name] == 0:
+			args = append(args, arg)
+			index[arg.Name] = len(args)
+			write("$"+strconv.Itoa(index[arg.Name]), i, true)
+		case args[index[arg.Name]-1].IsSlice != arg.IsSlice:
+			return "", nil, nil, fmt.Errorf("parser error | named arg %s is used both as a slice and a value", arg.Name)
+		default:
+			write("$"+strconv.Itoa(index[arg.Name]), i, true)
+		}
+		i = end
+	}
+
+	if len(args) == positional {
+		return sql, nil, nil, nil
+	}
+	if named > 0 && positional > 0 {
+		return "", nil, nil, fmt.Errorf("parser error | named and positional placeholders can not be mixed")
+	}
+	return out.String(), args, append(origin, len(sql)), nil
+}
+
+// SetNamedArgs renames the args after the named placeholders they are bound to and
+// marks slice args. A name used more than once must resolve to a single type; "any"
+// yields to a concrete type.
+func (t *ParsedQuery) SetNamedArgs(args []*NamedArg) error {
+	if len(args) != len(t.Arg) {
+		return fmt.Errorf("parser error | %d named placeholders but %d args", len(args), len(t.Arg))
+	}
+
+	types := make(map[string]string, len(args))
+	for i, arg := range args {
+		if arg.Name == "" {
+			continue
+		}
+		prev, ok := types[arg.Name]
+		switch {
+		case !ok || prev == "any":
+			types[arg.Name] = t.Arg[i].GoType
+		case t.Arg[i].GoType != prev && t.Arg[i].GoType != "any":
+			return fmt.Errorf("parser error | named arg %s is used as both %s and %s", arg.Name, prev, t.Arg[i].GoType)
+		}
+	}
+	for i, arg := range args {
+		field := NewField(t.Arg[i].Name, t.Arg[i].GoType)
+		if arg.Name != "" {
+			field = NewField(arg.Name, types[arg.Name])
+		}
+		field.IsSlice = arg.IsSlice
+		field.IsNamed = arg.Name != ""
+		t.Arg[i] = field
+	}
+	return nil
+}
+
+// scanNamedArg returns the named placeholder or slice marker starting at i and where it ends.
+func scanNamedArg(sql string, i int, numbered bool) (arg *NamedArg, end int) {
+	switch {
+	case sql[i] == ':' || sql[i] == '@':
+		// skip casts (::text), assignments (:=) and system variables (@@var)
+		if i > 0 && (sql[i-1] == sql[i] || db.IsIdentChar(sql[i-1])) {
+			return nil, i
+		}
+		end = i + 1
+		if end >= len(sql) || !db.IsIdentStart(sql[end]) {
+			return nil, i
+		}
+		for end < len(sql) && db.IsIdentChar(sql[end]) {
+			end++
+		}
+		return &NamedArg{Name: sql[i+1 : end]}, end
+	case !numbered && strings.HasPrefix(sql[i:], "?..."):
+		return &NamedArg{IsSlice: true}, i + len("?...")
+	}
+	for _, fn := range []string{"sqlc.arg(", "sqlc.slice("} {
+		if len(sql)-i <= len(fn) || !strings.EqualFold(sql[i:i+len(fn)], fn) || (i > 0 && db.IsIdentChar(sql[i-1])) {
+			continue
+		}
+		closing := strings.IndexByte(sql[i:], ')')
+		if closing == -1 {
+			return nil, i
+		}
+		name := strings.Trim(strings.TrimSpace(sql[i+len(fn):i+closing]), `'"`)
+		if name == "" {
+			return nil, i
+		}
+		for j := 0; j < len(name); j++ {
+			if !db.IsIdentChar(name[j]) || (j == 0 && !db.IsIdentStart(name[j])) {
+				return nil, i
+			}
+		}
+		return &NamedArg{Name: name, IsSlice: fn == "sqlc.slice("}, i + closing + 1
+	}
+	return nil, i
+}
+
+func isPositionalArg(sql string, i int, numbered bool) bool {
+	if numbered {
+		return sql[i] == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9'
+	}
+	return sql[i] == '?'
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteNamedArgs(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		numbered bool
+		query    string
+		args     []*NamedArg
+	}{
+		{"SELECT * FROM t WHERE id = ?", false, "SELECT * FROM t WHERE id = ?", nil},
+		{"SELECT * FROM t WHERE a = :email OR b = :email", false, "SELECT * FROM t WHERE a = ? OR b = ?", []*NamedArg{{Name: "email"}, {Name: "email"}}},
+		{"SELECT * FROM t WHERE a = :email OR b = :email", true, "SELECT * FROM t WHERE a = $1 OR b = $1", []*NamedArg{{Name: "email"}}},
+		{"SELECT * FROM t WHERE age >= @min_age AND name = sqlc.arg(name)", true, "SELECT * FROM t WHERE age >= $1 AND name = $2", []*NamedArg{{Name: "min_age"}, {Name: "name"}}},
+		{"SELECT a::text FROM t WHERE b = ':skip' AND c = :c -- :comment", true, "SELECT a::text FROM t WHERE b = ':skip' AND c = $1 -- :comment", []*NamedArg{{Name: "c"}}},
+		{"SELECT * FROM t WHERE a = ? AND id IN (?...)", false, "SELECT * FROM t WHERE a = ? AND id IN (?)", []*NamedArg{{}, {IsSlice: true}}},
+		{"SELECT * FROM t WHERE a = :a AND id IN (sqlc.slice(ids))", true, "SELECT * FROM t WHERE a = $1 AND id IN ($2)", []*NamedArg{{Name: "a"}, {Name: "ids", IsSlice: true}}},
+	} {
+		query, args, err := RewriteNamedArgs(test.input, test.numbered)
+		require.NoError(t, err)
+		require.Equal(t, test.query, query)
+		require.Equal(t, test.args, args)
+	}
+}
+
+func TestRewriteNamedArgsError(t *testing.T) {
+	_, _, err := RewriteNamedArgs("SELECT * FROM t WHERE a = :a AND b = ?", false)
+	require.Error(t, err)
+
+	_, _, err = RewriteNamedArgs("SELECT * FROM t WHERE a = :type", false)
+	require.Error(t, err)
+
+	_, _, err = RewriteNamedArgs("SELECT * FROM t WHERE a = :sql", false)
+	require.ErrorContains(t, err, "declared by the generated code")
+
+	_, _, err = RewriteNamedArgs("SELECT * FROM t WHERE a = :ids OR b IN (sqlc.slice(ids))", true)
+	require.Error(t, err)
+}
+
+func TestSetNamedArgs(t *testing.T) {
+	pq := &ParsedQuery{}
+	pq.Init("")
+	pq.Arg = append(pq.Arg, NewField("where_a", "any"), NewField("where_b", "string"), NewField("where_id_in_0", "int64"))
+
+	require.NoError(t, pq.SetNamedArgs([]*NamedArg{{Name: "email"}, {Name: "email"}, {IsSlice: true}}))
+	require.Equal(t, "email", pq.Arg[0].Name)
+	require.Equal(t, "string", pq.Arg[0].GoType)
+	require.Equal(t, "where_id_in_0", pq.Arg[2].Name)
+	require.True(t, pq.Arg[2].IsSlice)
+
+	pq.Arg[1].GoType = "int32"
+	require.Error(t, pq.SetNamedArgs([]*NamedArg{{Name: "email"}, {Name: "email"}, {}}))
+}
+
+func TestUniqueArgNames(t *testing.T) {
+	pq := &ParsedQuery{}
+	pq.Init("SELECT * FROM users WHERE age >= ? AND age <= ? AND id = :where_age OR id = :where_age")
+	pq.Arg = append(pq.Arg, NewField("where_age", "int32"), NewField("where_age", "int32"), NewField("where_age", "int64"), NewField("where_age", "int64"))
+	pq.Arg[2].IsNamed, pq.Arg[3].IsNamed = true, true
+	pq.UniqueArgNames()
+
+	names := make([]string, len(pq.Arg))
+	for i, arg := range pq.Arg {
+		names[i] = arg.Name
+	}
+	require.Equal(t, []string{"where_age_2", "where_age_3", "where_age", "where_age"}, names)
+}
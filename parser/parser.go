@@ -1,5 +1,7 @@
 package parser
 
+import "fmt"
+
 type Parser interface {
 	Parse(sql string) (*ParsedQuery, error)
 }
@@ -14,16 +16,31 @@ const (
 )
 
 type ParsedQuery struct {
-	QueryType QueryType
-	Query     string
+	QueryType   QueryType
+	Query       string
+	Placeholder int  // number of bind placeholders in the statement, counted from the AST
+	InsertOne   bool // insert of one VALUES row that is never skipped (no ON CONFLICT DO NOTHING), its RETURNING yields one row
 
-	Tpl []*ParsedQueryField // TODO
+	Tpl []*ParsedQueryField // #name# segments of the sql, formatted into it at runtime
 	Arg []*ParsedQueryField
 	Ret []*ParsedQueryField
 
+	Stmts []*ParsedQuery // statements of a multi-statement query, run in order in a transaction
+
 	// options
-	SelectSingle bool
-	InsertMulti  bool
+	SelectSingle     bool
+	InsertMulti      bool
+	UpdateNullIgnore bool     // the SET assignments of nil args are dropped at runtime, see db.OptionalSet
+	OrderBy          []string // sortable columns, the ORDER BY is set at runtime, see db.OrderBy
+	Page             *Page    // keyset of a paginated select, see db.Keyset
+}
+
+// Page is the keyset of a paginated select, the key columns and the ret fields holding their values.
+type Page struct {
+	Columns  []string
+	Fields   []string
+	Desc     bool
+	Numbered bool
 }
 
 func (t *ParsedQuery) Init(query string) {
@@ -33,6 +50,38 @@ func (t *ParsedQuery) Init(query string) {
 	t.Ret = make([]*ParsedQueryField, 0, 10)
 }
 
+// UniqueArgNames suffixes _2, _3 ... to the unnamed args sharing a name with another arg, so separate placeholders
+// are not merged into one generated arg (e.g. WHERE id = ? in each branch of a UNION).
+// A named arg keeps its name, its placeholders are bound to one arg.
+func (t *ParsedQuery) UniqueArgNames() {
+	used := make(map[string]bool, len(t.Arg))
+	for _, arg := range t.Arg {
+		if arg.IsNamed == true {
+			used[arg.Name] = true
+		}
+	}
+	for _, arg := range t.Arg {
+		if arg.IsNamed == false {
+			name := arg.Name
+			for n := 2; used[name] == true; n++ {
+				name = fmt.Sprintf("%s_%d", arg.Name, n)
+			}
+			arg.Name = name
+		}
+		used[arg.Name] = true
+	}
+}
+
+// HasRet reports whether the query returns a field of the name, e.g. an alias an ORDER BY refers to.
+func (t *ParsedQuery) HasRet(name string) bool {
+	for _, ret := range t.Ret {
+		if ret.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 func NewField(name, goType string) *ParsedQueryField {
 	return &ParsedQueryField{
 		Name:   name,
@@ -43,4 +92,11 @@ func NewField(name, goType string) *ParsedQueryField {
 type ParsedQueryField struct {
 	Name   string
 	GoType string
+
+	IsSlice bool // GoType is the item type of a list bound to one placeholder, e.g. IN (sqlc.slice(ids))
+	IsNamed bool // bound by name (:name), placeholders with the same name share one arg
+
+	IsOptional bool // the WHERE predicate or SET assignment of the arg is dropped at runtime when it is nil
+
+	Allowed []string // tpl only, the values the tpl may take, an identifier if empty
 }
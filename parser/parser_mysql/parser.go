@@ -2,6 +2,9 @@ package parser_mysql
 
 import (
 	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"ariga.io/atlas/sql/schema"
 	"github.com/gosuda/ornn/config"
@@ -20,22 +23,39 @@ func New(sch *config.Schema) parser.Parser {
 
 type Parser struct {
 	sch *config.Schema
+
+	strictErr error // first unknown column in strict mode, reset per Parse
 }
 
 func (p *Parser) Parse(sql string) (*parser.ParsedQuery, error) {
+	// 여러 statement 는 각각 파싱 후 트랜잭션으로 실행할 하나의 쿼리로 합침
+	if stmts := parser.SplitStatements(sql); len(stmts) > 1 {
+		return parser.ParseStatements(p, sql, stmts)
+	}
+
+	// 문법 에러 offset 은 named arg 치환 전 원래 sql 기준으로 되돌림
+	original := sql
+	sql, namedArgs, err := parser.RewriteNamedArgs(sql, false)
+	if err != nil {
+		return nil, err
+	}
+
 	sqlParser := sqlparser.New()
 	stmtNodes, _, err := sqlParser.Parse(sql, "", "")
 	if err != nil {
-		return nil, err
+		return nil, parser.NewSyntaxError(err, parser.NamedArgsOffset(original, false, syntaxErrorOffset(sql, err)))
 	}
 
 	pq := &parser.ParsedQuery{}
 	pq.Init(sql)
+	p.strictErr = nil
 
 	for _, stmtNode := range stmtNodes {
 		switch stmt := stmtNode.(type) {
 		case *ast.SelectStmt:
 			err = p.parseSelect(stmt, pq)
+		case *ast.SetOprStmt:
+			err = p.parseSetOpr(stmt, pq)
 		case *ast.InsertStmt:
 			err = p.parseInsert(stmt, pq)
 		case *ast.UpdateStmt:
@@ -49,6 +69,23 @@ func (p *Parser) Parse(sql string) (*parser.ParsedQuery, error) {
 			return nil, err
 		}
 	}
+	if p.strictErr != nil {
+		return nil, p.strictErr
+	}
+
+	// 모든 placeholder 는 타입이 정해진 인자로 매핑되어야 함
+	pq.Placeholder = countParamMarker(stmtNodes...)
+	if len(pq.Arg) < pq.Placeholder {
+		return nil, fmt.Errorf("parser error | %d of %d placeholders are not bound to a typed argument", pq.Placeholder-len(pq.Arg), pq.Placeholder)
+	}
+
+	// :name 형식의 인자는 이름을 그대로 사용, sqlc.slice(name) / ?... 는 slice 인자로 표시
+	if namedArgs != nil {
+		if err := pq.SetNamedArgs(namedArgs); err != nil {
+			return nil, err
+		}
+	}
+	pq.UniqueArgNames()
 	return pq, nil
 }
 
@@ -65,7 +102,102 @@ func (p *Parser) parseSelect(stmt *ast.SelectStmt, pq *parser.ParsedQuery) error
 	p.collectSelectFields(tbl, stmt.Fields, pq)
 
 	// WHERE
-	return p.parseWhere(stmt.Where, tbl, pq)
+	if err := p.parseWhere(stmt.Where, tbl, pq); err != nil {
+		return err
+	}
+
+	// HAVING
+	if stmt.Having != nil {
+		if err := p.parseCondition(stmt.Having.Expr, "having_", tbl, pq); err != nil {
+			return err
+		}
+	}
+
+	// ORDER BY
+	if err := p.parseOrderBy(stmt.OrderBy, tbl, pq); err != nil {
+		return err
+	}
+
+	// LIMIT / OFFSET
+	p.parseLimit(stmt.Limit, pq)
+	return nil
+}
+
+// parseSetOpr 는 UNION / INTERSECT / EXCEPT 를 파싱, 결과 컬럼은 첫 SELECT 를 따르고 나머지와 타입이 맞는지 확인
+func (p *Parser) parseSetOpr(stmt *ast.SetOprStmt, pq *parser.ParsedQuery) error {
+	pq.QueryType = parser.QueryTypeSelect
+
+	if err := p.parseSetOprSelectList(stmt.SelectList, pq); err != nil {
+		return err
+	}
+
+	// ORDER BY / LIMIT 는 첫 SELECT 의 테이블 기준
+	first := firstSelect(stmt.SelectList)
+	if first == nil {
+		return fmt.Errorf("parser error | set operation has no SELECT")
+	}
+	tbl, err := p.parseFrom(first.From)
+	if err != nil {
+		return err
+	}
+	if err := p.parseOrderBy(stmt.OrderBy, tbl, pq); err != nil {
+		return err
+	}
+	p.parseLimit(stmt.Limit, pq)
+	return nil
+}
+
+// parseSetOprSelectList 는 각 SELECT 를 순서대로 파싱해 ? 인자를 모으고, 결과 컬럼을 첫 SELECT 기준으로 맞춤
+func (p *Parser) parseSetOprSelectList(list *ast.SetOprSelectList, pq *parser.ParsedQuery) error {
+	for i, node := range list.Selects {
+		branch := &parser.ParsedQuery{}
+		branch.Init("")
+
+		var (
+			opr *ast.SetOprType
+			err error
+		)
+		switch sel := node.(type) {
+		case *ast.SelectStmt:
+			opr = sel.AfterSetOperator
+			err = p.parseSelect(sel, branch)
+		case *ast.SetOprSelectList: // 괄호로 묶인 set operation
+			opr = sel.AfterSetOperator
+			err = p.parseSetOprSelectList(sel, branch)
+		default:
+			err = fmt.Errorf("parser error | unsupported set operation node %T", sel)
+		}
+		if err != nil {
+			return err
+		}
+
+		pq.Arg = append(pq.Arg, branch.Arg...)
+		if i == 0 {
+			pq.Ret = append(pq.Ret, branch.Ret...)
+			continue
+		}
+		op := "UNION"
+		if opr != nil {
+			op = opr.String()
+		}
+		if err := parser.MergeSetOperationRet(op, pq.Ret, branch.Ret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func firstSelect(list *ast.SetOprSelectList) *ast.SelectStmt {
+	if list == nil || len(list.Selects) == 0 {
+		return nil
+	}
+	switch sel := list.Selects[0].(type) {
+	case *ast.SelectStmt:
+		return sel
+	case *ast.SetOprSelectList:
+		return firstSelect(sel)
+	}
+	return nil
 }
 
 func (p *Parser) collectSelectFields(tbl *schema.Table, fields *ast.FieldList, pq *parser.ParsedQuery) {
@@ -91,6 +223,7 @@ func (p *Parser) collectSelectFields(tbl *schema.Table, fields *ast.FieldList, p
 			}
 			pq.Ret = append(pq.Ret, parser.NewField(name, typ))
 		default:
+			p.checkColumns(tbl, f.Expr)
 			name := f.AsName.O
 			if name == "" {
 				name = "expr"
@@ -152,6 +285,7 @@ func (p *Parser) parseInsert(stmt *ast.InsertStmt, pq *parser.ParsedQuery) error
 				if col, ok := tbl.Column(colName); ok {
 					pq.Arg = append(pq.Arg, parser.NewField(argName, p.ConvType(col.Type)))
 				} else {
+					p.unknownColumn(tbl, colName)
 					pq.Arg = append(pq.Arg, parser.NewField(argName, "any"))
 				}
 			}
@@ -167,11 +301,15 @@ func (p *Parser) parseInsert(stmt *ast.InsertStmt, pq *parser.ParsedQuery) error
 
 		subPQ := &parser.ParsedQuery{}
 		subPQ.Init("")
-		sel, ok := stmt.Select.(*ast.SelectStmt)
-		if !ok {
+		switch sel := stmt.Select.(type) {
+		case *ast.SelectStmt:
+			err = p.parseSelect(sel, subPQ)
+		case *ast.SetOprStmt:
+			err = p.parseSetOpr(sel, subPQ)
+		default:
 			return fmt.Errorf("parser error | unsupported SELECT node type %T", stmt.Select)
 		}
-		if err := p.parseSelect(sel, subPQ); err != nil {
+		if err != nil {
 			return fmt.Errorf("parser error | parse inner SELECT: %w", err)
 		}
 		if len(targetCols) != len(subPQ.Ret) {
@@ -195,6 +333,7 @@ func (p *Parser) parseInsert(stmt *ast.InsertStmt, pq *parser.ParsedQuery) error
 			if col, ok := tbl.Column(colName); ok {
 				pq.Arg = append(pq.Arg, parser.NewField("dup_"+colName, p.ConvType(col.Type)))
 			} else {
+				p.unknownColumn(tbl, colName)
 				pq.Arg = append(pq.Arg, parser.NewField("dup_"+colName, "any"))
 			}
 		}
@@ -218,6 +357,9 @@ func (p *Parser) targetColumns(tbl *schema.Table, cols []*ast.ColumnName) ([]str
 	out := make([]string, len(cols))
 	for i, c := range cols {
 		out[i] = c.Name.O
+		if _, ok := tbl.Column(c.Name.O); !ok {
+			p.unknownColumn(tbl, c.Name.O)
+		}
 	}
 	return out, nil
 }
@@ -230,24 +372,37 @@ func (p *Parser) parseUpdate(stmt *ast.UpdateStmt, pq *parser.ParsedQuery) error
 		return err
 	}
 
-	// SET (값이 ? 일 때만 Arg 추가)
+	// SET (값에 ? 가 있을 때만 Arg 추가, e.g. age = age + ?)
 	for _, set := range stmt.List {
-		if !isParam(set.Expr) {
+		if !hasParamMarker(set.Expr) {
 			continue
 		}
 		colName := set.Column.Name.O
 		if p.IsReservedKeyword(colName) {
 			return fmt.Errorf("parser error | reserved keyword used as identifier: %s", colName)
 		}
+		typ := "any"
 		if col, ok := tbl.Column(colName); ok {
-			p.addArg(pq, "set_", colName, p.ConvType(col.Type))
+			typ = p.ConvType(col.Type)
 		} else {
-			p.addArg(pq, "set_", colName, "any")
+			p.unknownColumn(tbl, colName)
+		}
+		for i := countParamMarker(set.Expr); i > 0; i-- {
+			p.addArg(pq, "set_", colName, typ)
 		}
 	}
 
 	// WHERE
-	return p.parseWhere(stmt.Where, tbl, pq)
+	if err := p.parseWhere(stmt.Where, tbl, pq); err != nil {
+		return err
+	}
+
+	// ORDER BY / LIMIT
+	if err := p.parseOrderBy(stmt.Order, tbl, pq); err != nil {
+		return err
+	}
+	p.parseLimit(stmt.Limit, pq)
+	return nil
 }
 
 func (p *Parser) parseDelete(stmt *ast.DeleteStmt, pq *parser.ParsedQuery) error {
@@ -257,7 +412,16 @@ func (p *Parser) parseDelete(stmt *ast.DeleteStmt, pq *parser.ParsedQuery) error
 	if err != nil {
 		return err
 	}
-	return p.parseWhere(stmt.Where, tbl, pq)
+	if err := p.parseWhere(stmt.Where, tbl, pq); err != nil {
+		return err
+	}
+
+	// ORDER BY / LIMIT
+	if err := p.parseOrderBy(stmt.Order, tbl, pq); err != nil {
+		return err
+	}
+	p.parseLimit(stmt.Limit, pq)
+	return nil
 }
 func (p *Parser) parseFrom(tableClause *ast.TableRefsClause) (*schema.Table, error) {
 	if tableClause == nil || tableClause.TableRefs == nil {
@@ -270,7 +434,7 @@ func (p *Parser) parseFrom(tableClause *ast.TableRefsClause) (*schema.Table, err
 		tableName := ParseTableName(tableSources[0])
 		tbl, ok := p.sch.Table(tableName)
 		if !ok {
-			return nil, fmt.Errorf("parser error | not found table %s", tableName)
+			return nil, parser.UnknownTableError(p.sch.Schema, tableName)
 		}
 		return tbl, nil
 	}
@@ -283,7 +447,7 @@ func (p *Parser) parseFrom(tableClause *ast.TableRefsClause) (*schema.Table, err
 		tname := ParseTableName(ts)
 		baseTbl, ok := p.sch.Table(tname)
 		if !ok {
-			return nil, fmt.Errorf("parser error | not found table %s", tname)
+			return nil, parser.UnknownTableError(p.sch.Schema, tname)
 		}
 
 		var alias string
@@ -360,9 +524,52 @@ func ParseJoinToTables(join *ast.Join) []*ast.TableSource {
 	return nodes
 }
 func (p *Parser) parseWhere(where ast.ExprNode, tbl *schema.Table, pq *parser.ParsedQuery) error {
-	if where == nil {
+	return p.parseCondition(where, "where_", tbl, pq)
+}
+
+// parseOrderBy 는 ORDER BY 식 안의 ? 를 order_ 인자로 추출 (e.g. CASE WHEN id = ? THEN 0 ELSE 1 END)
+func (p *Parser) parseOrderBy(orderBy *ast.OrderByClause, tbl *schema.Table, pq *parser.ParsedQuery) error {
+	if orderBy == nil {
+		return nil
+	}
+	for _, item := range orderBy.Items {
+		// 결과 컬럼 이름 (alias) 도 쓸 수 있음
+		if column, ok := item.Expr.(*ast.ColumnNameExpr); ok && column.Name.Table.O == "" && pq.HasRet(column.Name.Name.O) {
+			continue
+		}
+		if err := p.parseCondition(item.Expr, "order_", tbl, pq); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseLimit 는 LIMIT/OFFSET 의 ? 를 int64 인자로 추출, MySQL 의 "LIMIT ?, ?" 는 offset 이 먼저 바인딩 됨
+func (p *Parser) parseLimit(limit *ast.Limit, pq *parser.ParsedQuery) {
+	if limit == nil {
+		return
+	}
+	_, count, _ := parseDriverValue(limit.Count)
+	_, offset, _ := parseDriverValue(limit.Offset)
+	if count != nil && offset != nil && offset.Offset < count.Offset {
+		p.addArg(pq, "", "offset", "int64")
+		p.addArg(pq, "", "limit", "int64")
+		return
+	}
+	if count != nil {
+		p.addArg(pq, "", "limit", "int64")
+	}
+	if offset != nil {
+		p.addArg(pq, "", "offset", "int64")
+	}
+}
+
+// parseCondition 은 조건식을 순회하며 ? 를 prefix 가 붙은 인자로 추출
+func (p *Parser) parseCondition(cond ast.ExprNode, prefix string, tbl *schema.Table, pq *parser.ParsedQuery) error {
+	if cond == nil {
 		return nil
 	}
+	p.checkColumns(tbl, cond)
 
 	var walk func(e ast.ExprNode) error
 	walk = func(e ast.ExprNode) error {
@@ -377,18 +584,16 @@ func (p *Parser) parseWhere(where ast.ExprNode, tbl *schema.Table, pq *parser.Pa
 			if err := walk(n.R); err != nil {
 				return err
 			}
-			// L = ?, R = col
+			// L = ?, R = col (or aggregate)
 			if isParam(n.L) {
-				if col, ok := n.R.(*ast.ColumnNameExpr); ok {
-					name, typ := p.resolveColumn(tbl, col)
-					pq.Arg = append(pq.Arg, parser.NewField("where_"+name, typ))
+				if name, typ, ok := p.resolveOperand(tbl, n.R); ok {
+					pq.Arg = append(pq.Arg, parser.NewField(prefix+name, typ))
 				}
 			}
-			// L = col, R = ?
+			// L = col (or aggregate), R = ?
 			if isParam(n.R) {
-				if col, ok := n.L.(*ast.ColumnNameExpr); ok {
-					name, typ := p.resolveColumn(tbl, col)
-					pq.Arg = append(pq.Arg, parser.NewField("where_"+name, typ))
+				if name, typ, ok := p.resolveOperand(tbl, n.L); ok {
+					pq.Arg = append(pq.Arg, parser.NewField(prefix+name, typ))
 				}
 			}
 			return nil
@@ -407,10 +612,10 @@ func (p *Parser) parseWhere(where ast.ExprNode, tbl *schema.Table, pq *parser.Pa
 			if col, ok := n.Expr.(*ast.ColumnNameExpr); ok {
 				name, typ := p.resolveColumn(tbl, col)
 				if isParam(n.Left) {
-					pq.Arg = append(pq.Arg, parser.NewField("where_"+name+"_from", typ))
+					pq.Arg = append(pq.Arg, parser.NewField(prefix+name+"_from", typ))
 				}
 				if isParam(n.Right) {
-					pq.Arg = append(pq.Arg, parser.NewField("where_"+name+"_to", typ))
+					pq.Arg = append(pq.Arg, parser.NewField(prefix+name+"_to", typ))
 				}
 			}
 			return nil
@@ -428,7 +633,7 @@ func (p *Parser) parseWhere(where ast.ExprNode, tbl *schema.Table, pq *parser.Pa
 							return err
 						}
 						if isParam(it) {
-							pq.Arg = append(pq.Arg, parser.NewField(fmt.Sprintf("where_%s_in_%d", name, i), typ))
+							pq.Arg = append(pq.Arg, parser.NewField(fmt.Sprintf("%s%s_in_%d", prefix, name, i), typ))
 						}
 					}
 				} else {
@@ -451,7 +656,7 @@ func (p *Parser) parseWhere(where ast.ExprNode, tbl *schema.Table, pq *parser.Pa
 			}
 			if col, ok := n.Expr.(*ast.ColumnNameExpr); ok && isParam(n.Pattern) {
 				name, typ := p.resolveColumn(tbl, col)
-				pq.Arg = append(pq.Arg, parser.NewField("where_"+name+"_like", typ))
+				pq.Arg = append(pq.Arg, parser.NewField(prefix+name+"_like", typ))
 			}
 			return nil
 
@@ -466,11 +671,31 @@ func (p *Parser) parseWhere(where ast.ExprNode, tbl *schema.Table, pq *parser.Pa
 			}
 			return nil
 
+		case *ast.CaseExpr:
+			// CASE [value] WHEN cond THEN result ... ELSE result END
+			for _, e := range []ast.ExprNode{n.Value, n.ElseClause} {
+				if e == nil {
+					continue
+				}
+				if err := walk(e); err != nil {
+					return err
+				}
+			}
+			for _, w := range n.WhenClauses {
+				if err := walk(w.Expr); err != nil {
+					return err
+				}
+				if err := walk(w.Result); err != nil {
+					return err
+				}
+			}
+			return nil
+
 		default:
 			return nil
 		}
 	}
-	return walk(where)
+	return walk(cond)
 }
 func (p *Parser) resolveColumn(tbl *schema.Table, c *ast.ColumnNameExpr) (name string, typ string) {
 	col := c.Name.Name.O
@@ -495,9 +720,44 @@ func (p *Parser) resolveColumn(tbl *schema.Table, c *ast.ColumnNameExpr) (name s
 			return display, p.ConvType(real.Type)
 		}
 	}
+	p.unknownColumn(tbl, display)
 	return display, "any"
 }
 
+// unknownColumn 은 strict 모드에서 스키마에 없는 컬럼 참조를 에러로 기록, 아니면 any 타입으로 진행
+func (p *Parser) unknownColumn(tbl *schema.Table, name string) {
+	if p.sch.Strict == true && p.strictErr == nil {
+		p.strictErr = parser.UnknownColumnError(tbl, name)
+	}
+}
+
+// resolveOperand 는 ? 와 비교되는 컬럼 또는 집계 함수의 이름과 타입을 찾음 (e.g. HAVING COUNT(*) > ?)
+func (p *Parser) resolveOperand(tbl *schema.Table, e ast.ExprNode) (name, typ string, ok bool) {
+	switch n := e.(type) {
+	case *ast.ColumnNameExpr:
+		name, typ = p.resolveColumn(tbl, n)
+		return name, typ, true
+	case *ast.AggregateFuncExpr:
+		name = strings.ToLower(n.F)
+		switch name {
+		case ast.AggFuncCount:
+			return name, "int64", true
+		case ast.AggFuncSum, ast.AggFuncAvg:
+			return name, parser.DecimalType(&parser.Type{}, p.sch.Decimal), true
+		case ast.AggFuncMax, ast.AggFuncMin:
+			if len(n.Args) == 1 {
+				if col, ok := n.Args[0].(*ast.ColumnNameExpr); ok {
+					_, typ = p.resolveColumn(tbl, col)
+					return name, typ, true
+				}
+			}
+		}
+		return name, "any", true
+	default:
+		return "", "", false
+	}
+}
+
 func parseDriverValue(node ast.ExprNode) (*test_driver.ValueExpr, *test_driver.ParamMarkerExpr, bool) {
 	switch data := node.(type) {
 	case *test_driver.ValueExpr:
@@ -548,6 +808,98 @@ func hasParamMarker(e ast.ExprNode) bool {
 	}
 }
 
+// paramCounter 는 구문 내 위치와 관계없이 모든 ? 를 셈
+type paramCounter struct {
+	count int
+}
+
+func (v *paramCounter) Enter(n ast.Node) (ast.Node, bool) {
+	if _, ok := n.(*test_driver.ParamMarkerExpr); ok {
+		v.count++
+	}
+	return n, false
+}
+
+func (v *paramCounter) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// columnFinder 는 서브쿼리를 제외한 모든 컬럼 참조를 모음
+type columnFinder struct {
+	columns []*ast.ColumnNameExpr
+}
+
+func (v *columnFinder) Enter(n ast.Node) (ast.Node, bool) {
+	switch data := n.(type) {
+	case *ast.SubqueryExpr:
+		return n, true
+	case *ast.ColumnNameExpr:
+		v.columns = append(v.columns, data)
+	}
+	return n, false
+}
+
+func (v *columnFinder) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+// checkColumns 는 식 안의 모든 컬럼 참조를 스키마에서 확인, 비교 대상이 ? 가 아니어도 strict 모드에서 에러
+func (p *Parser) checkColumns(tbl *schema.Table, e ast.ExprNode) {
+	if e == nil {
+		return
+	}
+	v := &columnFinder{}
+	e.Accept(v)
+	for _, column := range v.columns {
+		p.resolveColumn(tbl, column)
+	}
+}
+
+func countParamMarker[T ast.Node](nodes ...T) int {
+	v := &paramCounter{}
+	for _, n := range nodes {
+		n.Accept(v)
+	}
+	return v.count
+}
+
 func (p *Parser) addArg(pq *parser.ParsedQuery, prefix, name, typ string) {
 	pq.Arg = append(pq.Arg, parser.NewField(prefix+name, typ))
 }
+
+var syntaxErrorPos = regexp.MustCompile(`^line (\d+) column (\d+) near "((?s).*)"`)
+
+// syntaxErrorOffset 는 tidb parser 에러의 "line L column C near "..."" 에서 offset 을 찾음, near 는 에러 위치부터의 sql
+func syntaxErrorOffset(sql string, err error) int {
+	m := syntaxErrorPos.FindStringSubmatch(err.Error())
+	if m == nil {
+		return -1
+	}
+	line, _ := strconv.Atoi(m[1])
+	column, _ := strconv.Atoi(m[2])
+
+	lineStart := 0
+	for ; line > 1; line-- {
+		next := strings.IndexByte(sql[lineStart:], '\n')
+		if next == -1 {
+			return -1
+		}
+		lineStart += next + 1
+	}
+	if m[3] == "" {
+		return len(sql)
+	}
+	// column 은 토큰 끝을 가리키므로 column 이전에 시작하는 마지막 near 를 사용
+	offset := -1
+	for from := lineStart; from < lineStart+column && from < len(sql); {
+		idx := strings.Index(sql[from:], m[3])
+		if idx == -1 || from+idx >= lineStart+column {
+			break
+		}
+		offset, from = from+idx, from+idx+1
+	}
+	if offset == -1 {
+		offset = min(lineStart+column-1, len(sql))
+	}
+	return offset
+}
@@ -1,6 +1,7 @@
 package parser_mysql
 
 import (
+	"strings"
 	"testing"
 
 	"ariga.io/atlas/sql/schema"
@@ -218,3 +219,205 @@ WHERE u.id = ? AND o.amount > ?
 	require.Contains(t, args, "where_u.id")
 	require.Contains(t, args, "where_o.amount")
 }
+
+func TestSelectLimitOffset(t *testing.T) {
+	p := newParser(t)
+
+	pq := mustParse(t, p, `SELECT * FROM users WHERE age > ? ORDER BY id LIMIT ? OFFSET ?`)
+	require.Equal(t, []string{"where_age", "limit", "offset"}, argNames(pq))
+	require.Equal(t, 3, pq.Placeholder)
+	require.Equal(t, "int64", pq.Arg[1].GoType)
+	require.Equal(t, "int64", pq.Arg[2].GoType)
+
+	// MySQL "LIMIT offset, count" binds the offset first
+	pq = mustParse(t, p, `SELECT * FROM users LIMIT ?, ?`)
+	require.Equal(t, []string{"offset", "limit"}, argNames(pq))
+}
+
+func TestSelectHavingAndOrderBy(t *testing.T) {
+	p := newParser(t)
+	sql := `
+SELECT user_id
+FROM orders
+WHERE amount > ?
+GROUP BY user_id
+HAVING COUNT(*) > ? AND MAX(amount) < ?
+ORDER BY CASE WHEN user_id = ? THEN 0 ELSE 1 END
+LIMIT ?
+`
+	pq := mustParse(t, p, sql)
+
+	require.Equal(t, []string{"where_amount", "having_count", "having_max", "order_user_id", "limit"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[1].GoType)
+}
+
+func TestUpdateDeleteLimit(t *testing.T) {
+	p := newParser(t)
+
+	pq := mustParse(t, p, `UPDATE users SET age = age + ? WHERE name = ? ORDER BY id LIMIT ?`)
+	require.Equal(t, []string{"set_age", "where_name", "limit"}, argNames(pq))
+
+	pq = mustParse(t, p, `DELETE FROM users WHERE age < ? LIMIT ?`)
+	require.Equal(t, []string{"where_age", "limit"}, argNames(pq))
+}
+
+func TestUnassignedPlaceholder(t *testing.T) {
+	p := newParser(t)
+
+	_, err := p.Parse(`SELECT * FROM users WHERE id = ? OR ? > 5`)
+	require.Error(t, err)
+}
+
+func TestNamedArgs(t *testing.T) {
+	p := newParser(t)
+
+	pq := mustParse(t, p, `SELECT * FROM users WHERE name = :name OR (age >= @min_age AND name LIKE :name) LIMIT sqlc.arg(size)`)
+	require.Equal(t, "SELECT * FROM users WHERE name = ? OR (age >= ? AND name LIKE ?) LIMIT ?", pq.Query)
+	require.Equal(t, []string{"name", "min_age", "name", "size"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[3].GoType)
+}
+
+func TestSliceArgs(t *testing.T) {
+	p := newParser(t)
+
+	pq := mustParse(t, p, `SELECT * FROM users WHERE name = ? AND id IN (?...)`)
+	require.Equal(t, "SELECT * FROM users WHERE name = ? AND id IN (?)", pq.Query)
+	require.Equal(t, []string{"where_name", "where_id_in_0"}, argNames(pq))
+	require.False(t, pq.Arg[0].IsSlice)
+	require.True(t, pq.Arg[1].IsSlice)
+}
+
+func TestSelectSetOperation(t *testing.T) {
+	p := newParser(t)
+	pq := mustParse(t, p, `SELECT id, name FROM users WHERE age > ? UNION ALL SELECT id, user_id AS name FROM orders WHERE user_id = ? ORDER BY id LIMIT ?`)
+
+	require.Equal(t, parser.QueryTypeSelect, pq.QueryType)
+	require.Equal(t, []string{"id", "name"}, retNames(pq))
+	require.Equal(t, []string{"where_age", "where_user_id", "limit"}, argNames(pq))
+
+	// 같은 이름의 인자는 구분
+	pq = mustParse(t, p, `SELECT id FROM users WHERE id = ? EXCEPT SELECT id FROM users WHERE id = ?`)
+	require.Equal(t, []string{"where_id", "where_id_2"}, argNames(pq))
+
+	// 컬럼 수 불일치
+	_, err := p.Parse(`SELECT id, name FROM users INTERSECT SELECT id FROM orders`)
+	require.Error(t, err)
+}
+
+func TestStrictUnknownColumn(t *testing.T) {
+	s := newTestSchema(t)
+	s.Strict = true
+	p := New(s)
+
+	_, err := p.Parse(`SELECT id, nam FROM users WHERE id = ?`)
+	require.EqualError(t, err, "parser error | unknown column nam in table users, did you mean name?")
+	_, err = p.Parse(`INSERT INTO users (id, agee) VALUES (?, ?)`)
+	require.ErrorContains(t, err, "did you mean age?")
+	_, err = p.Parse(`SELECT u.id FROM users u JOIN orders o ON o.user_id = u.id WHERE o.amout > ?`)
+	require.ErrorContains(t, err, "unknown column o.amout in joined tables")
+
+	// ? 가 아닌 비교 대상, ORDER BY, 선택 목록의 컬럼도 확인
+	_, err = p.Parse(`SELECT id FROM users WHERE nmae = 'bob'`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT id FROM users ORDER BY nmae`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT LOWER(nmae) AS lower_name FROM users`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+
+	mustParse(t, p, `SELECT u.id FROM users u JOIN orders o ON o.user_id = u.id WHERE o.amount > ?`)
+	mustParse(t, p, `SELECT id, name AS n FROM users WHERE id = ? ORDER BY n`)
+}
+
+func TestSyntaxErrorOffset(t *testing.T) {
+	p := New(newTestSchema(t))
+
+	sql := "SELECT id\nFROM users\nWHERE id = ? AND name = = ?"
+	_, err := p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "="), parser.ErrorOffset(sql, err))
+
+	// named args 치환 전 sql 기준 offset
+	sql = "SELECT id FROM users WHERE name = :name_filter ORDER BY BY id"
+	_, err = p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "BY"), parser.ErrorOffset(sql, err))
+}
+
+func TestEnumColumn(t *testing.T) {
+	s := newTestSchema(t)
+	members := &schema.Table{Name: "org_members"}
+	members.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Type: &schema.IntegerType{T: "int"}}},
+		{Name: "role", Type: &schema.ColumnType{Raw: "enum('owner','admin')", Type: &schema.EnumType{T: "enum", Values: []string{"owner", "admin"}}}},
+		{Name: "prev_role", Type: &schema.ColumnType{Raw: "enum('owner','admin')", Type: &schema.EnumType{T: "enum", Values: []string{"owner", "admin"}}, Null: true}},
+	}
+	s.AddTables(members)
+	p := New(s)
+
+	pq := mustParse(t, p, `SELECT role, prev_role FROM org_members WHERE role = ?`)
+	require.Equal(t, "OrgMembersRole", pq.Ret[0].GoType)
+	require.Equal(t, "*OrgMembersPrevRole", pq.Ret[1].GoType)
+	require.Equal(t, "OrgMembersRole", pq.Arg[0].GoType)
+
+	pq = mustParse(t, p, `INSERT INTO org_members (id, role) VALUES (?, ?)`)
+	require.Equal(t, "OrgMembersRole", pq.Arg[1].GoType)
+}
+
+func TestNullable(t *testing.T) {
+	s := newTestSchema(t)
+	events := &schema.Table{Name: "events"}
+	events.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "bigint unsigned", Type: &schema.IntegerType{T: "bigint", Unsigned: true}}},
+		{Name: "title", Type: &schema.ColumnType{Raw: "varchar(100)", Type: &schema.StringType{T: "varchar", Size: 100}, Null: true}},
+		{Name: "seats", Type: &schema.ColumnType{Raw: "int unsigned", Type: &schema.IntegerType{T: "int", Unsigned: true}, Null: true}},
+		{Name: "role", Type: &schema.ColumnType{Raw: "enum('owner','admin')", Type: &schema.EnumType{T: "enum", Values: []string{"owner", "admin"}}, Null: true}},
+	}
+	s.AddTables(events)
+	p := New(s)
+
+	pq := mustParse(t, p, `SELECT id, title, seats, role FROM events`)
+	require.Equal(t, []string{"uint64", "*string", "*uint32", "*EventsRole"}, retTypes(pq))
+
+	s.Nullable = config.NullableSQL
+	pq = mustParse(t, p, `SELECT id, title, seats, role FROM events`)
+	require.Equal(t, []string{"uint64", "sql.NullString", "sql.Null[uint32]", "sql.Null[EventsRole]"}, retTypes(pq))
+
+	s.Nullable = config.NullableGeneric
+	pq = mustParse(t, p, `SELECT id, title, seats, role FROM events`)
+	require.Equal(t, []string{"uint64", "Null[string]", "Null[uint32]", "Null[EventsRole]"}, retTypes(pq))
+}
+
+func TestDecimal(t *testing.T) {
+	s := newTestSchema(t)
+	payments := &schema.Table{Name: "payments"}
+	payments.Columns = []*schema.Column{
+		{Name: "amount", Type: &schema.ColumnType{Raw: "decimal(20,2)", Type: &schema.DecimalType{T: "decimal", Precision: 20, Scale: 2}}},
+		{Name: "fee", Type: &schema.ColumnType{Raw: "decimal(10,4)", Type: &schema.DecimalType{T: "decimal", Precision: 10, Scale: 4}, Null: true}},
+		{Name: "units", Type: &schema.ColumnType{Raw: "decimal(10,0)", Type: &schema.DecimalType{T: "decimal", Precision: 10}}},
+	}
+	s.AddTables(payments)
+	p := New(s)
+
+	pq := mustParse(t, p, `SELECT amount, fee, units FROM payments`)
+	require.Equal(t, []string{"float64", "*float64", "float64"}, retTypes(pq))
+
+	s.Decimal = config.DecimalString
+	pq = mustParse(t, p, `SELECT amount, fee, units FROM payments WHERE amount > ?`)
+	require.Equal(t, []string{"string", "*string", "int64"}, retTypes(pq))
+	require.Equal(t, "string", pq.Arg[0].GoType)
+
+	pq = mustParse(t, p, `SELECT units FROM payments GROUP BY units HAVING SUM(amount) > ?`)
+	require.Equal(t, "string", pq.Arg[0].GoType)
+
+	s.TypeOverrides = []*config.TypeOverride{{DbType: "decimal", GoType: "decimal.Decimal", Import: "github.com/shopspring/decimal"}}
+	pq = mustParse(t, p, `SELECT amount, fee FROM payments`)
+	require.Equal(t, []string{"decimal.Decimal", "*decimal.Decimal"}, retTypes(pq))
+}
+
+func retTypes(pq *parser.ParsedQuery) []string {
+	out := make([]string, len(pq.Ret))
+	for i, f := range pq.Ret {
+		out[i] = f.GoType
+	}
+	return out
+}
@@ -9,8 +9,25 @@ import (
 )
 
 func (p *Parser) ConvType(colType *schema.ColumnType) (genType string) {
+	genType = p.convType(colType)
+	// null 은 config 의 nullable 표현으로 감쌈
+	if colType.Null {
+		genType = parser.NullableType(genType, p.sch.Nullable)
+	}
+	return genType
+}
+
+func (p *Parser) convType(colType *schema.ColumnType) (genType string) {
+	// config 의 type_overrides 가 우선
+	if goType, ok := p.sch.OverrideType(colType); ok {
+		return goType
+	}
+	// enum 은 테이블, 컬럼 이름으로 생성되는 string 타입
+	if enum := parser.EnumOf(p.sch.Schema, colType); enum != nil {
+		return enum.GoType
+	}
+
 	parseType := parser.ParseType(colType.Raw)
-	parseType.Nullable = colType.Null
 	switch parseType.Type {
 	case "bit":
 		switch {
@@ -25,20 +42,11 @@ func (p *Parser) ConvType(colType *schema.ColumnType) (genType string) {
 		default:
 			genType = "uint64"
 		}
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 
 	case "bool", "boolean":
 		genType = "bool"
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "char", "varchar", "tinytext", "text", "mediumtext", "longtext":
 		genType = "string"
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "tinyint":
 		switch {
 		case parseType.Prec == 1:
@@ -49,57 +57,35 @@ func (p *Parser) ConvType(colType *schema.ColumnType) (genType string) {
 				genType = "uint8"
 			}
 		}
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "smallint", "year":
 		genType = "int16"
 		if parseType.Unsigned {
 			genType = "uint16"
 		}
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "mediumint", "int", "integer":
 		genType = "int32"
 		if parseType.Unsigned {
 			genType = "uint32"
 		}
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "bigint":
 		genType = "int64"
 		if parseType.Unsigned {
 			genType = "uint64"
 		}
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "float":
 		genType = "float32"
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
-	case "decimal", "double":
+	case "decimal", "numeric":
+		genType = parser.DecimalType(parseType, p.sch.Decimal)
+	case "double":
 		genType = "float64"
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "binary", "blob", "longblob", "mediumblob", "tinyblob", "varbinary":
 		genType = "[]byte"
 	case "json":
 		genType = "json.RawMessage"
 	case "timestamp", "datetime", "date":
 		genType = "time.Time"
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	case "time":
 		genType = "string"
-		if parseType.Nullable {
-			genType = "*" + genType
-		}
 	default:
 		genType = "any"
 	}
@@ -3,6 +3,7 @@ package parser_postgres
 import (
 	"errors"
 	"fmt"
+	"strings"
 
 	"ariga.io/atlas/sql/schema"
 	sqlparser "github.com/cockroachdb/cockroachdb-parser/pkg/sql/parser"
@@ -19,18 +20,35 @@ func New(sch *config.Schema) parser.Parser {
 
 type Parser struct {
 	sch *config.Schema
+
+	args      map[tree.PlaceholderIdx]*parser.ParsedQueryField // typed args by placeholder index ($n), reset per Parse
+	strictErr error                                            // first unknown column in strict mode, reset per Parse
 }
 
 func (p *Parser) Parse(sql string) (*parser.ParsedQuery, error) {
-	stmtNodes, err := sqlparser.Parse(sql)
+	// 여러 statement 는 각각 파싱 후 트랜잭션으로 실행할 하나의 쿼리로 합침
+	if stmts := parser.SplitStatements(sql); len(stmts) > 1 {
+		return parser.ParseStatements(p, sql, stmts)
+	}
+
+	// 문법 에러 offset 은 named arg 치환 전 원래 sql 기준으로 되돌림
+	original := sql
+	sql, namedArgs, err := parser.RewriteNamedArgs(sql, true)
 	if err != nil {
 		return nil, err
+	}
+
+	stmtNodes, err := sqlparser.Parse(sql)
+	if err != nil {
+		return nil, parser.NewSyntaxError(err, parser.NamedArgsOffset(original, true, syntaxErrorOffset(err)))
 	} else if len(stmtNodes) != 1 {
-		panic("need more programming")
+		return nil, fmt.Errorf("parser error | expected a single statement but got %d", len(stmtNodes))
 	}
 
 	parsedQuery := &parser.ParsedQuery{}
 	parsedQuery.Init(sql)
+	p.args = make(map[tree.PlaceholderIdx]*parser.ParsedQueryField)
+	p.strictErr = nil
 	switch stmt := stmtNodes[0].AST.(type) {
 	case *tree.Select:
 		err = p.parseSelect(stmt, parsedQuery)
@@ -46,58 +64,110 @@ func (p *Parser) Parse(sql string) (*parser.ParsedQuery, error) {
 	if err != nil {
 		return nil, err
 	}
+	if p.strictErr != nil {
+		return nil, p.strictErr
+	}
+
+	// $1..$n 순서대로 인자 정렬, 타입이 정해지지 않은 placeholder 가 있으면 에러
+	parsedQuery.Placeholder = stmtNodes[0].NumPlaceholders
+	for i := 0; i < parsedQuery.Placeholder; i++ {
+		arg, ok := p.args[tree.PlaceholderIdx(i)]
+		if !ok {
+			return nil, fmt.Errorf("parser error | placeholder $%d is not bound to a typed argument", i+1)
+		}
+		parsedQuery.Arg = append(parsedQuery.Arg, arg)
+	}
+	// :name 형식의 인자는 이름을 그대로 사용, sqlc.slice(name) / ?... 는 slice 인자로 표시
+	if namedArgs != nil {
+		if err := parsedQuery.SetNamedArgs(namedArgs); err != nil {
+			return nil, err
+		}
+	}
+	parsedQuery.UniqueArgNames()
 
 	return parsedQuery, nil
 }
 
 func (p *Parser) parseSelect(stmt *tree.Select, parsedQuery *parser.ParsedQuery) error {
 	parsedQuery.QueryType = parser.QueryTypeSelect
+	_, err := p.parseSelectNode(stmt, parsedQuery)
+	return err
+}
 
-	selectStmt, ok := stmt.Select.(*tree.SelectClause)
-	if !ok {
-		panic("need more programming")
+// parseSelectNode 는 SELECT 절 (또는 UNION 등) 과 ORDER BY / LIMIT 를 파싱, 컬럼 타입 조회에 쓴 테이블을 반환
+func (p *Parser) parseSelectNode(stmt *tree.Select, parsedQuery *parser.ParsedQuery) (tbl *schema.Table, err error) {
+	switch selectStmt := stmt.Select.(type) {
+	case *tree.SelectClause:
+		tbl, err = p.parseSelectClause(selectStmt, parsedQuery)
+	case *tree.UnionClause:
+		tbl, err = p.parseUnion(selectStmt, parsedQuery)
+	case *tree.ParenSelect:
+		tbl, err = p.parseSelectNode(selectStmt.Select, parsedQuery)
+	default:
+		err = fmt.Errorf("parser error | not support select statement %T", selectStmt)
+	}
+	if err != nil {
+		return nil, err
 	}
+
+	// order by, 결과 컬럼 이름 (alias) 도 쓸 수 있음
+	for _, order := range stmt.OrderBy {
+		if name, ok := order.Expr.(*tree.UnresolvedName); ok == true && name.NumParts == 1 && parsedQuery.HasRet(name.Parts[0]) == true {
+			continue
+		}
+		p.parseCondition(order.Expr, "order_", tbl)
+	}
+
+	// limit / offset
+	p.parseLimit(stmt.Limit)
+	return tbl, nil
+}
+
+func (p *Parser) parseSelectClause(selectStmt *tree.SelectClause, parsedQuery *parser.ParsedQuery) (*schema.Table, error) {
 	// from
 	if len(selectStmt.From.Tables) != 1 {
 		panic("need more programming")
 	}
 	tbl, err := p.parseFrom(selectStmt.From.Tables[0])
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	// select
-	if len(selectStmt.Exprs) > 0 {
-		if _, ok := selectStmt.Exprs[0].Expr.(tree.UnqualifiedStar); ok == true {
-			for _, col := range tbl.Columns {
-				parsedQuery.Ret = append(parsedQuery.Ret, &parser.ParsedQueryField{
-					Name:   col.Name,
-					GoType: p.ConvType(col.Type.Raw),
-				})
-			}
-		} else {
-			for _, selectExpr := range selectStmt.Exprs {
-				switch fieldExpr := selectExpr.Expr.(type) {
-				case *tree.ColumnItem:
-					colName := fieldExpr.ColumnName.String()
-					col, ok := tbl.Column(colName)
-					if ok != true {
-						parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(colName, "any"))
-					} else {
-						parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(colName, p.ConvType(col.Type.Raw)))
-					}
-				}
-			}
-		}
+	err = p.parseSelectExprs(selectStmt.Exprs, tbl, parsedQuery)
+	if err != nil {
+		return nil, err
 	}
+
 	// where
 	if selectStmt.Where != nil {
 		err = p.parseWhere(selectStmt.Where, tbl, parsedQuery)
 		if err != nil {
-			return err
+			return nil, err
 		}
 	}
-	return nil
+
+	// having
+	if selectStmt.Having != nil {
+		p.parseCondition(selectStmt.Having.Expr, "having_", tbl)
+	}
+	return tbl, nil
+}
+
+// parseUnion 은 UNION / INTERSECT / EXCEPT 를 파싱, 결과 컬럼은 왼쪽 SELECT 를 따르고 오른쪽과 타입이 맞는지 확인
+// 인자는 $n 인덱스로 모이므로 양쪽 모두 그대로 바인딩, ORDER BY 는 왼쪽 테이블 기준으로 조회
+func (p *Parser) parseUnion(union *tree.UnionClause, parsedQuery *parser.ParsedQuery) (*schema.Table, error) {
+	tbl, err := p.parseSelectNode(union.Left, parsedQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	right := &parser.ParsedQuery{}
+	right.Init("")
+	if _, err := p.parseSelectNode(union.Right, right); err != nil {
+		return nil, err
+	}
+	return tbl, parser.MergeSetOperationRet(union.Type.String(), parsedQuery.Ret, right.Ret)
 }
 
 func (p *Parser) parseInsert(stmt *tree.Insert, parsedQuery *parser.ParsedQuery) error {
@@ -112,7 +182,7 @@ func (p *Parser) parseInsert(stmt *tree.Insert, parsedQuery *parser.ParsedQuery)
 	// values
 	rows := stmt.Rows.Select.(*tree.ValuesClause).Rows
 	if len(rows) != 1 {
-		return errors.New("bulk query is invalid, use bulk options")
+		return errors.New("parser error | multi-row values is invalid, write a single row and set the bulk option")
 	}
 	colNames := make([]string, len(tbl.Columns))
 	if len(stmt.Columns) == 0 { // insert all fields
@@ -123,10 +193,8 @@ func (p *Parser) parseInsert(stmt *tree.Insert, parsedQuery *parser.ParsedQuery)
 			panic("not same column and value count")
 		}
 		for i, list := range rows[0] {
-			if _, _, placeHolder, ok := ParseDriverValue(list); !ok {
-				panic("need more programming")
-			} else if placeHolder != nil {
-				parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colNames[i], p.ConvType(tbl.Columns[i].Type.Raw)))
+			for _, placeHolder := range FindPlaceholders(list) {
+				p.bindArg(placeHolder, "val_"+colNames[i], p.convColumnType(tbl.Columns[i].Type))
 			}
 		}
 	} else { // insert specific fields
@@ -134,19 +202,71 @@ func (p *Parser) parseInsert(stmt *tree.Insert, parsedQuery *parser.ParsedQuery)
 			panic("not same column and value count")
 		}
 		for i, list := range rows[0] {
-			if _, _, paramMarkerExpr, ok := ParseDriverValue(list); !ok {
-				panic("need more programming")
-			} else if paramMarkerExpr != nil {
-				colName := stmt.Columns[i].String()
-				col, ok := tbl.Column(colName)
+			colName := string(stmt.Columns[i])
+			col, ok := tbl.Column(colName)
+			if ok != true {
+				p.unknownColumn(tbl, colName)
+			}
+			for _, placeHolder := range FindPlaceholders(list) {
 				if ok != true {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colName, "any"))
+					p.bindArg(placeHolder, "val_"+colName, "any")
 				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colName, p.ConvType(col.Type.Raw)))
+					p.bindArg(placeHolder, "val_"+colName, p.convColumnType(col.Type))
 				}
 			}
 		}
 	}
+
+	// on conflict
+	if stmt.OnConflict != nil {
+		err = p.parseOnConflict(stmt.OnConflict, tbl)
+		if err != nil {
+			return err
+		}
+	}
+	// 한 행이고 DO NOTHING 으로 건너뛰지 않으면 RETURNING 은 항상 한 행
+	parsedQuery.InsertOne = len(rows) == 1 && (stmt.OnConflict == nil || stmt.OnConflict.DoNothing == false)
+
+	// returning
+	return p.parseReturning(stmt.Returning, tbl, parsedQuery)
+}
+
+// parseOnConflict 는 ON CONFLICT (cols) [WHERE ...] DO UPDATE SET ... [WHERE ...] 의 placeholder 를 인자로 추출
+func (p *Parser) parseOnConflict(onConflict *tree.OnConflict, tbl *schema.Table) error {
+	// conflict target
+	for _, name := range onConflict.Columns {
+		if _, ok := tbl.Column(string(name)); ok != true {
+			return fmt.Errorf("parser error | not found conflict column %s in table %s", name, tbl.Name)
+		}
+	}
+	if onConflict.ArbiterPredicate != nil {
+		p.parseCondition(onConflict.ArbiterPredicate, "conflict_", tbl)
+	}
+	if onConflict.DoNothing == true {
+		return nil
+	}
+
+	// do update set
+	for _, setExpr := range onConflict.Exprs {
+		if len(setExpr.Names) != 1 {
+			return fmt.Errorf("parser error | not support tuple assignment in on conflict")
+		}
+		colName := string(setExpr.Names[0])
+		col, ok := tbl.Column(colName)
+		if ok != true {
+			p.unknownColumn(tbl, colName)
+		}
+		for _, placeHolder := range FindPlaceholders(setExpr.Expr) {
+			if ok != true {
+				p.bindArg(placeHolder, "dup_"+colName, "any")
+			} else {
+				p.bindArg(placeHolder, "dup_"+colName, p.convColumnType(col.Type))
+			}
+		}
+	}
+	if onConflict.Where != nil {
+		p.parseCondition(onConflict.Where.Expr, "dup_where_", tbl)
+	}
 	return nil
 }
 
@@ -162,12 +282,17 @@ func (p *Parser) parseUpdate(stmt *tree.Update, parsedQuery *parser.ParsedQuery)
 		if len(setExpr.Names) != 1 {
 			panic("need more programming")
 		}
-		colName := setExpr.Names[0].String()
+		colName := string(setExpr.Names[0])
 		col, ok := tbl.Column(colName)
 		if ok != true {
-			parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colName, "any"))
-		} else {
-			parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colName, p.ConvType(col.Type.Raw)))
+			p.unknownColumn(tbl, colName)
+		}
+		for _, placeHolder := range FindPlaceholders(setExpr.Expr) {
+			if ok != true {
+				p.bindArg(placeHolder, "val_"+colName, "any")
+			} else {
+				p.bindArg(placeHolder, "val_"+colName, p.convColumnType(col.Type))
+			}
 		}
 	}
 
@@ -178,7 +303,9 @@ func (p *Parser) parseUpdate(stmt *tree.Update, parsedQuery *parser.ParsedQuery)
 			return err
 		}
 	}
-	return nil
+
+	// returning
+	return p.parseReturning(stmt.Returning, tbl, parsedQuery)
 }
 
 func (p *Parser) parseDelete(stmt *tree.Delete, parsedQuery *parser.ParsedQuery) error {
@@ -197,9 +324,50 @@ func (p *Parser) parseDelete(stmt *tree.Delete, parsedQuery *parser.ParsedQuery)
 			return err
 		}
 	}
+
+	// returning
+	return p.parseReturning(stmt.Returning, tbl, parsedQuery)
+}
+
+// parseSelectExprs 는 SELECT 절 (또는 RETURNING 절) 의 컬럼을 반환 필드로 추출
+func (p *Parser) parseSelectExprs(exprs tree.SelectExprs, tbl *schema.Table, parsedQuery *parser.ParsedQuery) error {
+	for _, selectExpr := range exprs {
+		if _, ok := selectExpr.Expr.(tree.UnqualifiedStar); ok == true {
+			for _, col := range tbl.Columns {
+				parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(col.Name, p.convColumnType(col.Type)))
+			}
+			continue
+		}
+
+		p.checkColumns(selectExpr.Expr, tbl)
+		name, goType, ok := p.resolveOperand(selectExpr.Expr, tbl)
+		if selectExpr.As != "" {
+			name = string(selectExpr.As)
+		} else if ok != true {
+			return fmt.Errorf("parser error | select expression %s needs an alias", selectExpr.Expr)
+		}
+		if ok != true {
+			goType = "any"
+		}
+		parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(name, goType))
+	}
 	return nil
 }
 
+// parseReturning 은 INSERT/UPDATE/DELETE 의 RETURNING 컬럼을 반환 필드로 추출
+func (p *Parser) parseReturning(returning tree.ReturningClause, tbl *schema.Table, parsedQuery *parser.ParsedQuery) error {
+	exprs, ok := returning.(*tree.ReturningExprs)
+	if ok != true {
+		return nil
+	}
+	for _, expr := range *exprs {
+		if len(FindPlaceholders(expr.Expr)) > 0 {
+			return fmt.Errorf("parser error | placeholder in returning clause is not supported")
+		}
+	}
+	return p.parseSelectExprs(tree.SelectExprs(*exprs), tbl, parsedQuery)
+}
+
 func (p *Parser) parseFrom(tableClause tree.TableExpr) (tbl *schema.Table, err error) {
 
 	var tableName string
@@ -213,39 +381,108 @@ func (p *Parser) parseFrom(tableClause tree.TableExpr) (tbl *schema.Table, err e
 	}
 	tbl, ok := p.sch.Table(tableName)
 	if ok != true {
-		return nil, fmt.Errorf("parser error | not found table %s", tableName)
+		return nil, parser.UnknownTableError(p.sch.Schema, tableName)
 	}
 	return tbl, nil
 }
 
+// unknownColumn 은 strict 모드에서 스키마에 없는 컬럼 참조를 에러로 기록, 아니면 any 타입으로 진행
+func (p *Parser) unknownColumn(tbl *schema.Table, name string) {
+	if p.sch.Strict == true && p.strictErr == nil {
+		p.strictErr = parser.UnknownColumnError(tbl, name)
+	}
+}
+
 func (p *Parser) parseWhere(where *tree.Where, tbl *schema.Table, parsedQuery *parser.ParsedQuery) (err error) {
-	whereFields := ParseWhereToFields(where.Expr)
-	for _, where := range whereFields {
+	p.parseCondition(where.Expr, "where_", tbl)
+	return nil
+}
+
+// checkColumns 는 식 안의 모든 컬럼 참조를 스키마에서 확인, 비교 대상이 placeholder 가 아니어도 strict 모드에서 에러
+func (p *Parser) checkColumns(expr tree.Expr, tbl *schema.Table) {
+	for _, name := range FindColumns(expr) {
+		if _, ok := tbl.Column(name.Parts[0]); ok != true {
+			p.unknownColumn(tbl, name.Parts[0])
+		}
+	}
+}
+
+// parseCondition 은 조건식의 placeholder 를 비교 대상 컬럼(또는 집계 함수) 이름에 prefix 를 붙여 인자로 추출
+func (p *Parser) parseCondition(expr tree.Expr, prefix string, tbl *schema.Table) {
+	p.checkColumns(expr, tbl)
+	for _, where := range ParseWhereToFields(expr) {
 		// left 의 column 을 인자로 추출
 		if placeHolder, _ := where.right.(*tree.Placeholder); placeHolder != nil {
-			if data, ok := where.left.(*tree.UnresolvedName); ok == true {
-				colName := data.Parts[0]
-				col, ok := tbl.Column(colName)
-				if ok != true {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, "any"))
-				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, p.ConvType(col.Type.Raw)))
-				}
+			if name, goType, ok := p.resolveOperand(where.left, tbl); ok == true {
+				p.bindArg(placeHolder, prefix+name+where.suffix, goType)
 			}
 		}
 		// right 의 column 을 인자로 추출
 		if placeHolder, _ := where.left.(*tree.Placeholder); placeHolder != nil {
-			if data, ok := where.right.(*tree.UnresolvedName); ok == true {
-				colName := data.Parts[0]
-				col, ok := tbl.Column(colName)
-				if ok != true {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, "any"))
-				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, p.ConvType(col.Type.Raw)))
+			if name, goType, ok := p.resolveOperand(where.right, tbl); ok == true {
+				p.bindArg(placeHolder, prefix+name+where.suffix, goType)
+			}
+		}
+		// col IN ($1, $2, ...)
+		if tuple, _ := where.right.(*tree.Tuple); tuple != nil {
+			if name, goType, ok := p.resolveOperand(where.left, tbl); ok == true {
+				for i, item := range tuple.Exprs {
+					if placeHolder, _ := item.(*tree.Placeholder); placeHolder != nil {
+						p.bindArg(placeHolder, fmt.Sprintf("%s%s_in_%d", prefix, name, i), goType)
+					}
 				}
 			}
 		}
 	}
+}
 
-	return nil
+// resolveOperand 는 placeholder 와 비교되는 컬럼 또는 집계 함수의 이름과 타입을 찾음 (e.g. HAVING count(*) > $1)
+func (p *Parser) resolveOperand(expr tree.Expr, tbl *schema.Table) (name, goType string, ok bool) {
+	switch data := expr.(type) {
+	case *tree.UnresolvedName:
+		colName := data.Parts[0]
+		col, ok := tbl.Column(colName)
+		if ok != true {
+			p.unknownColumn(tbl, colName)
+			return colName, "any", true
+		}
+		return colName, p.convColumnType(col.Type), true
+	case *tree.FuncExpr:
+		name = strings.ToLower(data.Func.String())
+		switch name {
+		case "count":
+			return name, "int64", true
+		case "sum", "avg":
+			return name, parser.DecimalType(&parser.Type{}, p.sch.Decimal), true
+		case "min", "max":
+			if len(data.Exprs) == 1 {
+				if _, goType, ok := p.resolveOperand(data.Exprs[0], tbl); ok == true {
+					return name, goType, true
+				}
+			}
+		}
+		return name, "any", true
+	default:
+		return "", "", false
+	}
+}
+
+// parseLimit 는 LIMIT/OFFSET 의 placeholder 를 int64 인자로 추출
+func (p *Parser) parseLimit(limit *tree.Limit) {
+	if limit == nil {
+		return
+	}
+	if placeHolder, _ := limit.Count.(*tree.Placeholder); placeHolder != nil {
+		p.bindArg(placeHolder, "limit", "int64")
+	}
+	if placeHolder, _ := limit.Offset.(*tree.Placeholder); placeHolder != nil {
+		p.bindArg(placeHolder, "offset", "int64")
+	}
+}
+
+// bindArg 는 placeholder 인덱스($n)에 인자를 연결, 같은 $n 이 반복되면 처음 것을 유지
+func (p *Parser) bindArg(placeHolder *tree.Placeholder, name, goType string) {
+	if _, ok := p.args[placeHolder.Idx]; !ok {
+		p.args[placeHolder.Idx] = parser.NewField(name, goType)
+	}
 }
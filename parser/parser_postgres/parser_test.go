@@ -2,10 +2,15 @@ package parser_postgres
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
 	sqlparser "github.com/cockroachdb/cockroachdb-parser/pkg/sql/parser"
 	"github.com/cockroachdb/cockroachdb-parser/pkg/sql/sem/tree"
+	"github.com/gosuda/ornn/config"
+	"github.com/gosuda/ornn/parser"
 	"github.com/stretchr/testify/require"
 )
 
@@ -29,3 +34,327 @@ func TestParseMysqlInsert(t *testing.T) {
 	insertStmt := stmts[0].AST.(*tree.Insert)
 	fmt.Println(insertStmt.Table)
 }
+
+func newTestParser(t *testing.T) parser.Parser {
+	t.Helper()
+
+	users := &schema.Table{Name: "users"}
+	users.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: "bigint"}}},
+		{Name: "name", Type: &schema.ColumnType{Raw: "character varying", Type: &schema.StringType{T: "character varying"}}},
+		{Name: "age", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: "integer"}}},
+	}
+
+	s := &config.Schema{}
+	s.Schema = &schema.Schema{}
+	s.AddTables(users)
+	return New(s)
+}
+
+func argNames(pq *parser.ParsedQuery) []string {
+	out := make([]string, len(pq.Arg))
+	for i, f := range pq.Arg {
+		out[i] = f.Name
+	}
+	return out
+}
+
+func TestParseLimitOffset(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("SELECT * FROM users WHERE age > $1 ORDER BY id LIMIT $2 OFFSET $3")
+	require.NoError(t, err)
+	require.Equal(t, []string{"where_age", "limit", "offset"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[1].GoType)
+
+	// args follow the placeholder index, not the order they appear in
+	pq, err = p.Parse("SELECT * FROM users WHERE name = $2 AND age BETWEEN $3 AND $4 LIMIT $1")
+	require.NoError(t, err)
+	require.Equal(t, []string{"limit", "where_name", "where_age_from", "where_age_to"}, argNames(pq))
+}
+
+func TestParseHaving(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("SELECT age FROM users WHERE id IN ($1, $2) GROUP BY age HAVING count(*) > $3")
+	require.NoError(t, err)
+	require.Equal(t, []string{"where_id_in_0", "where_id_in_1", "having_count"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[2].GoType)
+}
+
+func TestParseUpdateLiteral(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("UPDATE users SET name = 'bob', age = age + $1 WHERE id = $2")
+	require.NoError(t, err)
+	require.Equal(t, []string{"val_age", "where_id"}, argNames(pq))
+	require.Equal(t, 2, pq.Placeholder)
+}
+
+func TestParseUnassignedPlaceholder(t *testing.T) {
+	p := newTestParser(t)
+
+	_, err := p.Parse("SELECT * FROM users WHERE id = $1 OR length($2) > 5")
+	require.Error(t, err)
+}
+
+func TestParseNamedArgs(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("UPDATE users SET name = :name WHERE id = :id OR name = :name")
+	require.NoError(t, err)
+	require.Equal(t, "UPDATE users SET name = $1 WHERE id = $2 OR name = $1", pq.Query)
+	require.Equal(t, []string{"name", "id"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[1].GoType)
+}
+
+func TestParseSliceArgs(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("SELECT * FROM users WHERE id IN (sqlc.slice(ids)) AND name = :name")
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM users WHERE id IN ($1) AND name = $2", pq.Query)
+	require.Equal(t, []string{"ids", "name"}, argNames(pq))
+	require.True(t, pq.Arg[0].IsSlice)
+	require.Equal(t, "int64", pq.Arg[0].GoType)
+}
+
+func TestParseReturning(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("INSERT INTO users (name) VALUES ($1) RETURNING id, name AS user_name")
+	require.NoError(t, err)
+	require.Equal(t, []string{"val_name"}, argNames(pq))
+	require.Len(t, pq.Ret, 2)
+	require.Equal(t, "id", pq.Ret[0].Name)
+	require.Equal(t, "int64", pq.Ret[0].GoType)
+	require.Equal(t, "user_name", pq.Ret[1].Name)
+	require.True(t, pq.InsertOne)
+
+	pq, err = p.Parse("DELETE FROM users WHERE id = $1 RETURNING *")
+	require.NoError(t, err)
+	require.Len(t, pq.Ret, 3)
+
+	pq, err = p.Parse("UPDATE users SET name = $1 WHERE id = $2")
+	require.NoError(t, err)
+	require.Empty(t, pq.Ret)
+
+	pq, err = p.Parse("SELECT id, count(*) AS cnt FROM users GROUP BY id")
+	require.NoError(t, err)
+	require.Equal(t, "int64", pq.Ret[1].GoType)
+	require.Equal(t, "cnt", pq.Ret[1].Name)
+}
+
+func TestParseOnConflict(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) DO UPDATE SET name = $3, age = users.age + $4 WHERE users.age < $5")
+	require.NoError(t, err)
+	require.Equal(t, []string{"val_id", "val_name", "dup_name", "dup_age", "dup_where_age"}, argNames(pq))
+	require.Equal(t, "int32", pq.Arg[3].GoType)
+
+	pq, err = p.Parse("INSERT INTO users (id, name) VALUES ($1, $2) ON CONFLICT (id) WHERE age > $3 DO NOTHING")
+	require.NoError(t, err)
+	require.Equal(t, []string{"val_id", "val_name", "conflict_age"}, argNames(pq))
+	require.False(t, pq.InsertOne)
+
+	_, err = p.Parse("INSERT INTO users (id) VALUES ($1) ON CONFLICT (email) DO NOTHING")
+	require.Error(t, err)
+}
+
+func TestParseSetOperation(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse(`SELECT id, age FROM users WHERE name = $1 UNION SELECT age, id FROM users WHERE age > $2 ORDER BY id LIMIT $3`)
+	require.NoError(t, err)
+	require.Equal(t, parser.QueryTypeSelect, pq.QueryType)
+	require.Equal(t, []string{"where_name", "where_age", "limit"}, argNames(pq))
+	require.Equal(t, "id", pq.Ret[0].Name)
+	require.Equal(t, "int64", pq.Ret[0].GoType) // bigint UNION integer
+	require.Equal(t, "int64", pq.Ret[1].GoType)
+
+	pq, err = p.Parse(`(SELECT id FROM users WHERE id = $1) INTERSECT (SELECT id FROM users WHERE id = $2)`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"where_id", "where_id_2"}, argNames(pq))
+
+	_, err = p.Parse(`SELECT id FROM users EXCEPT SELECT name FROM users`)
+	require.Error(t, err)
+}
+
+func TestParseMultiStatement(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse(`UPDATE users SET age = age - :amount WHERE id = :from;
+		UPDATE users SET age = $1 WHERE id = $2;
+		SELECT id, name FROM users WHERE id = $1;`)
+	require.NoError(t, err)
+	require.Len(t, pq.Stmts, 3)
+	require.Equal(t, parser.QueryTypeSelect, pq.QueryType)
+	require.Equal(t, 5, pq.Placeholder)
+	require.Equal(t, []string{"amount", "from", "val_age", "where_id", "where_id_2"}, argNames(pq))
+	require.Len(t, pq.Ret, 2)
+	require.Equal(t, "UPDATE users SET age = $1 WHERE id = $2", pq.Stmts[1].Query)
+
+	_, err = p.Parse(`UPDATE users SET age = 1; SELECT * FROM nothing`)
+	require.ErrorContains(t, err, "statement 2")
+}
+
+func TestParseStrict(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+
+	// 기본은 any 타입으로 진행
+	pq, err := p.Parse(`SELECT id FROM users WHERE nmae = $1`)
+	require.NoError(t, err)
+	require.Equal(t, "any", pq.Arg[0].GoType)
+
+	p.sch.Strict = true
+	_, err = p.Parse(`SELECT id FROM users WHERE nmae = $1`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`UPDATE users SET agee = 1 WHERE id = $1`)
+	require.ErrorContains(t, err, "unknown column agee in table users, did you mean age?")
+
+	// placeholder 가 아닌 비교 대상, ORDER BY, 선택 목록의 컬럼도 확인
+	_, err = p.Parse(`SELECT id FROM users WHERE nmae = 'bob'`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT id FROM users ORDER BY nmae`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT lower(nmae) AS lower_name FROM users`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT id, name AS n FROM users WHERE id = $1 ORDER BY n`)
+	require.NoError(t, err)
+	_, err = p.Parse(`SELECT * FROM usrs`)
+	require.EqualError(t, err, "parser error | unknown table usrs, did you mean users?")
+}
+
+func TestParseSyntaxErrorOffset(t *testing.T) {
+	p := newTestParser(t)
+
+	sql := "SELECT id\nFROM users\nWHERE id = $1 AND name = = $2"
+	_, err := p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "="), parser.ErrorOffset(sql, err))
+
+	// 여러 문장이면 전체 sql 기준 offset
+	sql = "DELETE FROM users WHERE id = $1;\nSELECT id FROM users WHERE WHERE id = $2"
+	_, err = p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "WHERE"), parser.ErrorOffset(sql, err))
+
+	// named args 치환 전 sql 기준 offset
+	sql = "SELECT id FROM users WHERE name = :name_filter ORDER BY BY id"
+	_, err = p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "id"), parser.ErrorOffset(sql, err))
+}
+
+func TestParseEnumColumn(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	role := &schema.ColumnType{Raw: "USER-DEFINED", Type: &schema.EnumType{T: "member_role", Values: []string{"owner", "admin"}}}
+	members := &schema.Table{Name: "org_members"}
+	members.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "bigint", Type: &schema.IntegerType{T: "bigint"}}},
+		{Name: "role", Type: role},
+	}
+	p.sch.AddTables(members)
+
+	pq, err := p.Parse(`SELECT id, role FROM org_members WHERE role = $1`)
+	require.NoError(t, err)
+	require.Equal(t, "MemberRole", pq.Ret[1].GoType)
+	require.Equal(t, "MemberRole", pq.Arg[0].GoType)
+
+	pq, err = p.Parse(`UPDATE org_members SET role = $1 WHERE id = $2`)
+	require.NoError(t, err)
+	require.Equal(t, "MemberRole", pq.Arg[0].GoType)
+
+	pq, err = p.Parse(`INSERT INTO org_members (id, role) VALUES ($1, $2)`)
+	require.NoError(t, err)
+	require.Equal(t, "MemberRole", pq.Arg[1].GoType)
+}
+
+func TestParseTypeOverrides(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	accounts := &schema.Table{Name: "accounts"}
+	accounts.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "uuid", Type: &schema.UUIDType{T: "uuid"}}},
+		{Name: "owner_uuid", Type: &schema.ColumnType{Raw: "uuid", Type: &schema.UUIDType{T: "uuid"}, Null: true}},
+		{Name: "balance", Type: &schema.ColumnType{Raw: "numeric(20,2)", Type: &schema.DecimalType{T: "numeric"}}},
+		{Name: "attrs", Type: &schema.ColumnType{Raw: "hstore", Type: &schema.UnsupportedType{T: "hstore"}}},
+	}
+	p.sch.AddTables(accounts)
+
+	pq, err := p.Parse(`SELECT id, owner_uuid, balance, attrs FROM accounts`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"string", "*string", "float64", "hstore.Hstore"}, retTypes(pq))
+
+	p.sch.TypeOverrides = []*config.TypeOverride{
+		{DbType: "numeric", GoType: "decimal.Decimal", Import: "github.com/shopspring/decimal"},
+		{DbType: "uuid", GoType: "uuid.UUID", Import: "github.com/google/uuid"},
+		{ColumnPattern: "*_uuid", GoType: "OwnerID"},
+		{Column: "accounts.id", GoType: "AccountID"},
+	}
+	pq, err = p.Parse(`SELECT id, owner_uuid, balance, attrs FROM accounts`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AccountID", "*OwnerID", "decimal.Decimal", "hstore.Hstore"}, retTypes(pq))
+
+	pq, err = p.Parse(`SELECT name FROM users WHERE id = $1`)
+	require.NoError(t, err)
+	require.Equal(t, "string", pq.Ret[0].GoType)
+}
+
+func TestParseArrayRangeTypes(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	events := &schema.Table{Name: "events"}
+	events.Columns = []*schema.Column{
+		{Name: "tags", Type: &schema.ColumnType{Raw: "ARRAY", Type: &postgres.ArrayType{T: "text[]", Type: &schema.StringType{T: "text"}}}},
+		{Name: "scores", Type: &schema.ColumnType{Raw: "ARRAY", Type: &postgres.ArrayType{T: "smallint[]", Type: &schema.IntegerType{T: "smallint"}}}},
+		{Name: "stamps", Type: &schema.ColumnType{Raw: "ARRAY", Type: &postgres.ArrayType{T: "timestamp with time zone[]", Type: &schema.TimeType{T: "timestamp with time zone"}}}},
+		{Name: "during", Type: &schema.ColumnType{Raw: "tstzrange", Type: &postgres.RangeType{T: "tstzrange"}}},
+		{Name: "seats", Type: &schema.ColumnType{Raw: "int4range", Type: &postgres.RangeType{T: "int4range"}, Null: true}},
+		{Name: "length", Type: &schema.ColumnType{Raw: "interval", Type: &postgres.IntervalType{T: "interval"}}},
+		{Name: "network", Type: &schema.ColumnType{Raw: "cidr", Type: &postgres.NetworkType{T: "cidr"}}},
+	}
+	p.sch.AddTables(events)
+
+	pq, err := p.Parse(`SELECT tags, scores, stamps, during, seats, length, network FROM events`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"[]string", "[]int32", "[]string", "Range[time.Time]", "*Range[int32]", "Interval", "string"}, retTypes(pq))
+
+	pq, err = p.Parse(`UPDATE events SET tags = $1 WHERE network = $2`)
+	require.NoError(t, err)
+	require.Equal(t, "[]string", pq.Arg[0].GoType)
+	require.False(t, pq.Arg[0].IsSlice)
+
+	require.Equal(t, "[]int64", p.ConvType("bigint[]"))
+	require.Equal(t, "[]float64", p.ConvType("double precision[]"))
+}
+
+func TestParseDecimal(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	payments := &schema.Table{Name: "payments"}
+	payments.Columns = []*schema.Column{
+		{Name: "amount", Type: &schema.ColumnType{Raw: "numeric(20,2)", Type: &schema.DecimalType{T: "numeric", Precision: 20, Scale: 2}}},
+		{Name: "total", Type: &schema.ColumnType{Raw: "numeric", Type: &schema.DecimalType{T: "numeric"}, Null: true}},
+		{Name: "units", Type: &schema.ColumnType{Raw: "numeric(12,0)", Type: &schema.DecimalType{T: "numeric", Precision: 12}}},
+		{Name: "bands", Type: &schema.ColumnType{Raw: "numrange", Type: &postgres.RangeType{T: "numrange"}}},
+	}
+	p.sch.AddTables(payments)
+
+	pq, err := p.Parse(`SELECT amount, total, units, bands FROM payments`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"float64", "*float64", "float64", "Range[float64]"}, retTypes(pq))
+
+	p.sch.Decimal = config.DecimalString
+	pq, err = p.Parse(`SELECT amount, total, units, bands FROM payments WHERE amount > $1`)
+	require.NoError(t, err)
+	require.Equal(t, []string{"string", "*string", "int64", "Range[string]"}, retTypes(pq))
+	require.Equal(t, "string", pq.Arg[0].GoType)
+	require.Equal(t, "[]string", p.ConvType("numeric(20,2)[]"))
+}
+
+func retTypes(pq *parser.ParsedQuery) []string {
+	out := make([]string, len(pq.Ret))
+	for i, f := range pq.Ret {
+		out[i] = f.GoType
+	}
+	return out
+}
@@ -1,6 +1,11 @@
 package parser_postgres
 
-import "github.com/cockroachdb/cockroachdb-parser/pkg/sql/sem/tree"
+import (
+	"strings"
+
+	"github.com/cockroachdb/cockroachdb-parser/pkg/sql/sem/tree"
+	"github.com/cockroachdb/errors"
+)
 
 func ParseDriverValue(node tree.Expr) (*tree.NumVal, *tree.StrVal, *tree.Placeholder, bool) {
 	switch data := node.(type) {
@@ -16,9 +21,10 @@ func ParseDriverValue(node tree.Expr) (*tree.NumVal, *tree.StrVal, *tree.Placeho
 }
 
 type binaryExpr struct {
-	left  tree.Expr
-	right tree.Expr
-	op    string
+	left   tree.Expr
+	right  tree.Expr
+	op     string
+	suffix string // arg name suffix, e.g. _from / _to for BETWEEN
 }
 
 func ParseWhereToFields(whereExpr tree.Expr) []*binaryExpr {
@@ -40,6 +46,15 @@ func ParseWhereToFields(whereExpr tree.Expr) []*binaryExpr {
 	case *tree.OrExpr:
 		fields = append(ParseWhereToFields(data.Left), fields...)
 		fields = append(fields, ParseWhereToFields(data.Right)...)
+	case *tree.RangeCond:
+		fields = append(fields,
+			&binaryExpr{left: data.Left, right: data.From, op: ">=", suffix: "_from"},
+			&binaryExpr{left: data.Left, right: data.To, op: "<=", suffix: "_to"},
+		)
+	case *tree.CaseExpr:
+		for _, when := range data.Whens {
+			fields = append(fields, ParseWhereToFields(when.Cond)...)
+		}
 	case *tree.ParenExpr:
 		fields = append(fields, ParseWhereToFields(data.Expr)...)
 	case *tree.NotExpr:
@@ -52,8 +67,76 @@ func ParseWhereToFields(whereExpr tree.Expr) []*binaryExpr {
 		// do nothing
 	case *tree.Subquery:
 		// do nothing
+	case *tree.UnresolvedName:
+		// do nothing
 	default:
 		panic("parser error | not support where type")
 	}
 	return fields
 }
+
+// placeholderFinder collects every placeholder in an expression tree.
+type placeholderFinder struct {
+	placeholders []*tree.Placeholder
+}
+
+func (v *placeholderFinder) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	if placeHolder, ok := expr.(*tree.Placeholder); ok {
+		v.placeholders = append(v.placeholders, placeHolder)
+	}
+	return true, expr
+}
+
+func (v *placeholderFinder) VisitPost(expr tree.Expr) tree.Expr {
+	return expr
+}
+
+func FindPlaceholders(expr tree.Expr) []*tree.Placeholder {
+	v := &placeholderFinder{}
+	tree.WalkExpr(v, expr)
+	return v.placeholders
+}
+
+// columnFinder collects every column reference in an expression tree, not descending into subqueries.
+type columnFinder struct {
+	columns []*tree.UnresolvedName
+}
+
+func (v *columnFinder) VisitPre(expr tree.Expr) (recurse bool, newExpr tree.Expr) {
+	switch data := expr.(type) {
+	case *tree.Subquery:
+		return false, expr
+	case *tree.UnresolvedName:
+		if data.Star == false {
+			v.columns = append(v.columns, data)
+		}
+	}
+	return true, expr
+}
+
+func (v *columnFinder) VisitPost(expr tree.Expr) tree.Expr {
+	return expr
+}
+
+func FindColumns(expr tree.Expr) []*tree.UnresolvedName {
+	v := &columnFinder{}
+	tree.WalkExpr(v, expr)
+	return v.columns
+}
+
+// syntaxErrorOffset 는 cockroach parser 에러의 "source SQL" detail 에서 caret(^) 이 가리키는 offset 을 반환
+func syntaxErrorOffset(err error) int {
+	for _, detail := range errors.GetAllDetails(err) {
+		body, ok := strings.CutPrefix(detail, "source SQL:\n")
+		if ok != true {
+			continue
+		}
+		caret := strings.LastIndexByte(body, '\n')
+		if caret == -1 {
+			continue
+		}
+		lineStart := strings.LastIndexByte(body[:caret], '\n') + 1
+		return lineStart + len(body[caret+1:]) - 1
+	}
+	return -1
+}
@@ -3,9 +3,34 @@ package parser_postgres
 import (
 	"strings"
 
+	"ariga.io/atlas/sql/postgres"
+	"ariga.io/atlas/sql/schema"
 	"github.com/gosuda/ornn/parser"
 )
 
+// convColumnType 는 컬럼의 go 타입, type_overrides 와 enum 을 먼저 확인
+func (p *Parser) convColumnType(colType *schema.ColumnType) (genType string) {
+	// config 의 type_overrides 가 우선
+	if goType, ok := p.sch.OverrideType(colType); ok {
+		genType = goType
+	} else if enum := parser.EnumOf(p.sch.Schema, colType); enum != nil {
+		genType = enum.GoType
+	} else if arrayType, ok := colType.Type.(*postgres.ArrayType); ok && arrayType.T != "" {
+		// 배열의 data_type 은 ARRAY 라 원소 타입이 있는 format_type 을 사용
+		genType = p.ConvType(arrayType.T)
+		if enum := parser.EnumOf(p.sch.Schema, &schema.ColumnType{Type: arrayType.Type}); enum != nil {
+			genType = "[]" + enum.GoType
+		}
+	} else {
+		genType = p.ConvType(colType.Raw)
+	}
+	// null 은 config 의 nullable 표현으로 감쌈
+	if colType.Null {
+		genType = parser.NullableType(genType, p.sch.Nullable)
+	}
+	return genType
+}
+
 func (p *Parser) ConvType(dbType string) (genType string) {
 	parseType := parser.ParseType(dbType)
 
@@ -13,7 +38,10 @@ func (p *Parser) ConvType(dbType string) (genType string) {
 		genType = p.ConvType(parseType.Type[len("SETOF "):])
 		return "[]" + genType
 	}
-	typNullable := parseType.Nullable && !parseType.IsArray
+	if parseType.IsArray {
+		// 배열은 pq.Array 로 bind, scan 하는 slice
+		return "[]" + arrayElemType(p.ConvType(strings.TrimSuffix(strings.TrimSpace(dbType), "[]")))
+	}
 	typ := parseType.Type
 	switch {
 	case typ == `"char"`:
@@ -31,68 +59,66 @@ func (p *Parser) ConvType(dbType string) (genType string) {
 	switch typ {
 	case "boolean":
 		genType = "bool"
-		if typNullable {
-			genType = "sql.NullBool"
-		}
-	case "bpchar", "character varying", "character", "inet", "money", "text", "name":
+	case "bpchar", "character varying", "character", "inet", "cidr", "macaddr", "macaddr8", "money", "text", "name", "uuid":
 		genType = "string"
-		if typNullable {
-			genType = "sql.NullString"
-		}
 	case "smallint":
 		genType = "int16"
-		if typNullable {
-			genType = "sql.NullInt64"
-		} else if parseType.Unsigned {
+		if parseType.Unsigned {
 			genType = "uint16"
 		}
 	case "integer":
 		genType = "int32"
-		if typNullable {
-			genType = "sql.NullInt64"
-		} else if parseType.Unsigned {
+		if parseType.Unsigned {
 			genType = "uint32"
 		}
 	case "bigint":
 		genType = "int64"
-		if typNullable {
-			genType = "sql.NullInt64"
-		} else if parseType.Unsigned {
+		if parseType.Unsigned {
 			genType = "uint64"
 		}
 	case "real":
 		genType = "float32"
-		if typNullable {
-			genType = "sql.NullFloat64"
-		}
-	case "double precision", "numeric":
+	case "double precision":
 		genType = "float64"
-		if typNullable {
-			genType = "sql.NullFloat64"
-		}
+	case "numeric", "decimal":
+		genType = parser.DecimalType(parseType, p.sch.Decimal)
 	case "date", "timestamp with time zone", "time with time zone", "time without time zone", "timestamp without time zone":
 		genType = "time.Time"
-		if typNullable {
-			genType = "sql.NullTime"
-		}
 	case "bit":
 		genType = "uint8"
-		if typNullable {
-			genType = "*uint8"
-		}
-	case "any", "bit varying", "bytea", "interval", "json", "jsonb", "xml":
-		// TODO: write custom type for interval marshaling
-		// TODO: marshalling for json types
+	case "any", "bit varying", "bytea", "xml":
 		genType = "[]byte"
+	case "interval":
+		genType = "Interval"
+	case "int4range":
+		genType = "Range[int32]"
+	case "int8range":
+		genType = "Range[int64]"
+	case "numrange":
+		// 범위의 경계는 정밀도가 없는 numeric
+		genType = "Range[" + parser.DecimalType(&parser.Type{}, p.sch.Decimal) + "]"
+	case "tsrange", "tstzrange", "daterange":
+		genType = "Range[time.Time]"
+	case "json", "jsonb":
+		// custom_field_types 로 지정하면 JSON[T] 로 생성
+		genType = "json.RawMessage"
 	case "hstore":
 		genType = "hstore.Hstore"
-	case "uuid":
-		genType = "uuidbType.UUID"
-		if typNullable {
-			genType = "uuidbType.NullUUID"
-		}
 	default:
 		genType = "any"
 	}
 	return genType
 }
+
+// arrayElemType 는 pq.Array 가 scan 할 수 있는 배열 원소 타입, 그 외의 원소는 text 표현인 string
+func arrayElemType(goType string) string {
+	switch goType {
+	case "bool", "int32", "int64", "float32", "float64", "string", "[]byte":
+		return goType
+	case "int16", "uint8":
+		return "int32"
+	case "uint16", "uint32":
+		return "int64"
+	}
+	return "string"
+}
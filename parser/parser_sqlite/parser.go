@@ -2,6 +2,7 @@ package parser_sqlite
 
 import (
 	"fmt"
+	"strings"
 
 	"ariga.io/atlas/sql/schema"
 	"github.com/CovenantSQL/sqlparser"
@@ -17,19 +18,49 @@ func New(sch *config.Schema) parser.Parser {
 
 type Parser struct {
 	sch *config.Schema
+
+	args       map[int]*parser.ParsedQueryField // typed args by placeholder position (?), reset per Parse
+	returning  string                           // RETURNING clause split off the statement, reset per Parse
+	onConflict string                           // ON CONFLICT clause split off the insert, reset per Parse
+	strictErr  error                            // first unknown column in strict mode, reset per Parse
 }
 
 func (p *Parser) Parse(sql string) (*parser.ParsedQuery, error) {
-	stmtNode, err := sqlparser.Parse(sql)
+	// 여러 statement 는 각각 파싱 후 트랜잭션으로 실행할 하나의 쿼리로 합침
+	if stmts := parser.SplitStatements(sql); len(stmts) > 1 {
+		return parser.ParseStatements(p, sql, stmts)
+	}
+
+	// 문법 에러 offset 은 named arg 치환 전 원래 sql 기준으로 되돌림
+	original := sql
+	sql, namedArgs, err := parser.RewriteNamedArgs(sql, false)
 	if err != nil {
 		return nil, err
 	}
+
+	// sqlparser 는 RETURNING, ON CONFLICT 를 지원하지 않아 분리 후 따로 파싱
+	stmtSql := sql
+	p.returning, p.onConflict = "", ""
+	if idx := parser.IndexKeyword(stmtSql, "RETURNING"); idx != -1 {
+		stmtSql, p.returning = stmtSql[:idx], strings.TrimSpace(stmtSql[idx+len("RETURNING"):])
+	}
+	if idx := parser.IndexKeyword(stmtSql, "ON CONFLICT"); idx != -1 {
+		stmtSql, p.onConflict = stmtSql[:idx], stmtSql[idx:]
+	}
+
+	stmtNode, err := sqlparser.Parse(stmtSql)
+	if err != nil {
+		return nil, parser.NewSyntaxError(err, parser.NamedArgsOffset(original, false, syntaxErrorOffset(stmtSql, err)))
+	}
 	parsedQuery := &parser.ParsedQuery{}
 	parsedQuery.Init(sql)
+	p.args = make(map[int]*parser.ParsedQueryField)
+	p.strictErr = nil
 
 	switch stmt := stmtNode.(type) {
-	case *sqlparser.Select:
-		err = p.parseSelect(stmt, parsedQuery)
+	case *sqlparser.Select, *sqlparser.Union:
+		parsedQuery.QueryType = parser.QueryTypeSelect
+		_, err = p.parseSelectNode(stmt.(sqlparser.SelectStatement), parsedQuery)
 	case *sqlparser.Insert:
 		err = p.parseInsert(stmt, parsedQuery)
 	case *sqlparser.Update:
@@ -42,45 +73,112 @@ func (p *Parser) Parse(sql string) (*parser.ParsedQuery, error) {
 	if err != nil {
 		return nil, err
 	}
+	if p.strictErr != nil {
+		return nil, p.strictErr
+	}
+	if p.onConflict != "" && parsedQuery.QueryType != parser.QueryTypeInsert {
+		return nil, fmt.Errorf("parser error | on conflict is only valid in insert")
+	}
+
+	// ? 순서대로 인자 정렬, 타입이 정해지지 않은 placeholder 가 있으면 에러
+	// (ON CONFLICT 절의 placeholder 는 parseOnConflict 에서 미리 더해짐)
+	parsedQuery.Placeholder += len(FindValArgs(stmtNode))
+	for i := 1; i <= parsedQuery.Placeholder; i++ {
+		arg, ok := p.args[i]
+		if !ok {
+			return nil, fmt.Errorf("parser error | placeholder #%d is not bound to a typed argument", i)
+		}
+		parsedQuery.Arg = append(parsedQuery.Arg, arg)
+	}
+	// :name 형식의 인자는 이름을 그대로 사용, sqlc.slice(name) / ?... 는 slice 인자로 표시
+	if namedArgs != nil {
+		if err := parsedQuery.SetNamedArgs(namedArgs); err != nil {
+			return nil, err
+		}
+	}
+	parsedQuery.UniqueArgNames()
 
 	return parsedQuery, nil
 }
 
-func (p *Parser) parseSelect(stmt *sqlparser.Select, parsedQuery *parser.ParsedQuery) error {
-	parsedQuery.QueryType = parser.QueryTypeSelect
+// parseSelectNode 는 SELECT 또는 UNION 을 파싱, 컬럼 타입 조회에 쓴 테이블을 반환
+func (p *Parser) parseSelectNode(stmt sqlparser.SelectStatement, parsedQuery *parser.ParsedQuery) (*schema.Table, error) {
+	switch data := stmt.(type) {
+	case *sqlparser.Select:
+		return p.parseSelect(data, parsedQuery)
+	case *sqlparser.Union:
+		return p.parseUnion(data, parsedQuery)
+	case *sqlparser.ParenSelect:
+		return p.parseSelectNode(data.Select, parsedQuery)
+	default:
+		return nil, fmt.Errorf("parser error | not support select statement %T", data)
+	}
+}
+
+// parseUnion 은 UNION 을 파싱, 결과 컬럼은 왼쪽 SELECT 를 따르고 오른쪽과 타입이 맞는지 확인
+// 인자는 ? 위치로 모이므로 양쪽 모두 그대로 바인딩, ORDER BY 는 왼쪽 테이블 기준으로 조회
+func (p *Parser) parseUnion(stmt *sqlparser.Union, parsedQuery *parser.ParsedQuery) (*schema.Table, error) {
+	tbl, err := p.parseSelectNode(stmt.Left, parsedQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	right := &parser.ParsedQuery{}
+	right.Init("")
+	if _, err := p.parseSelectNode(stmt.Right, right); err != nil {
+		return nil, err
+	}
+	if err := parser.MergeSetOperationRet(strings.ToUpper(stmt.Type), parsedQuery.Ret, right.Ret); err != nil {
+		return nil, err
+	}
+
+	// order by, 결과 컬럼 이름 (alias) 도 쓸 수 있음
+	for _, order := range stmt.OrderBy {
+		if column, ok := order.Expr.(*sqlparser.ColName); ok == true && column.Qualifier.IsEmpty() == true && parsedQuery.HasRet(column.Name.String()) == true {
+			continue
+		}
+		p.parseCondition(order.Expr, "order_", tbl)
+	}
+
+	// limit / offset
+	p.parseLimit(stmt.Limit)
+	return tbl, nil
+}
+
+func (p *Parser) parseSelect(stmt *sqlparser.Select, parsedQuery *parser.ParsedQuery) (*schema.Table, error) {
 	tbl, err := p.parseFrom(stmt.From)
+	if err != nil {
+		return nil, err
+	}
 
 	// select
-	for _, selectExpr := range stmt.SelectExprs {
-		switch data := selectExpr.(type) {
-		case *sqlparser.StarExpr:
-			for _, col := range tbl.Columns {
-				parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(col.Name, p.ConvType(col.Type)))
-			}
-			break
-		case *sqlparser.AliasedExpr:
-			switch data2 := data.Expr.(type) {
-			case *sqlparser.ColName:
-				colName := data2.Name.String()
-				if col, _ := tbl.Column(colName); col != nil {
-					parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(col.Name, p.ConvType(col.Type)))
-				} else {
-					parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(col.Name, "any"))
-				}
-			default:
-				panic("need more programming")
-			}
-		default:
-			panic("need more programming")
-		}
+	err = p.parseSelectExprs(stmt.SelectExprs, tbl, parsedQuery)
+	if err != nil {
+		return nil, err
 	}
 
 	// where
 	err = p.parseWhere(stmt.Where, tbl, parsedQuery)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	// having
+	if stmt.Having != nil {
+		p.parseCondition(stmt.Having.Expr, "having_", tbl)
+	}
+
+	// order by, 결과 컬럼 이름 (alias) 도 쓸 수 있음
+	for _, order := range stmt.OrderBy {
+		if column, ok := order.Expr.(*sqlparser.ColName); ok == true && column.Qualifier.IsEmpty() == true && parsedQuery.HasRet(column.Name.String()) == true {
+			continue
+		}
+		p.parseCondition(order.Expr, "order_", tbl)
+	}
+
+	// limit / offset
+	p.parseLimit(stmt.Limit)
+	return tbl, nil
 }
 
 func (p *Parser) parseInsert(stmt *sqlparser.Insert, parsedQuery *parser.ParsedQuery) error {
@@ -89,14 +187,14 @@ func (p *Parser) parseInsert(stmt *sqlparser.Insert, parsedQuery *parser.ParsedQ
 	var tableName string = stmt.Table.Name.String()
 	var tbl *schema.Table
 	if tbl, _ = p.sch.Table(tableName); tbl == nil {
-		return fmt.Errorf("table not found | %s", tableName)
+		return parser.UnknownTableError(p.sch.Schema, tableName)
 	}
 
 	// values
 	// insert fields
 	vals, _ := stmt.Rows.(sqlparser.Values)
 	if len(vals) != 1 {
-		panic("bulk query is invalid, use bulk options")
+		return fmt.Errorf("parser error | multi-row values is invalid, write a single row and set the bulk option")
 	}
 	colNames := make([]string, len(tbl.Columns))
 	if len(stmt.Columns) == 0 { // insert all fields
@@ -108,10 +206,8 @@ func (p *Parser) parseInsert(stmt *sqlparser.Insert, parsedQuery *parser.ParsedQ
 		}
 
 		for i, list := range vals[0] {
-			if _, paramMarkerExpr, ok := ParseDriverValue(list); !ok {
-				panic("need more programming")
-			} else if paramMarkerExpr != nil {
-				parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colNames[i], p.ConvType(tbl.Columns[i].Type)))
+			for _, valArg := range FindValArgs(list) {
+				p.bindArg(valArg, "val_"+colNames[i], p.ConvType(tbl.Columns[i].Type))
 			}
 		}
 	} else { // insert specific fields
@@ -119,25 +215,121 @@ func (p *Parser) parseInsert(stmt *sqlparser.Insert, parsedQuery *parser.ParsedQ
 			panic("not same column and value count")
 		}
 		for i, list := range vals[0] {
-			if _, paramMarkerExpr, ok := ParseDriverValue(list); !ok {
-				panic("need more programming")
-			} else if paramMarkerExpr != nil {
-				colName := stmt.Columns[i].String()
-				col, ok := tbl.Column(colName)
+			colName := stmt.Columns[i].String()
+			col, ok := tbl.Column(colName)
+			if ok != true {
+				p.unknownColumn(tbl, colName)
+			}
+			for _, valArg := range FindValArgs(list) {
 				if ok != true {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colName, "any"))
+					p.bindArg(valArg, "val_"+colName, "any")
 				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("val_"+colName, p.ConvType(col.Type)))
+					p.bindArg(valArg, "val_"+colName, p.ConvType(col.Type))
 				}
 			}
 		}
 	}
-	// ondup
+	// ondup - mysql 의 ON DUPLICATE KEY UPDATE 는 sqlite 에 없음
 	if len(stmt.OnDup) != 0 {
-		panic("need more programming")
+		return fmt.Errorf("parser error | not support on duplicate key update, use on conflict")
 	}
 
-	return nil
+	// on conflict
+	if p.onConflict != "" {
+		placeholder, err := p.parseOnConflict(tbl, len(FindValArgs(stmt)))
+		if err != nil {
+			return err
+		}
+		parsedQuery.Placeholder += placeholder
+	}
+	// 한 행이고 DO NOTHING / INSERT IGNORE 로 건너뛰지 않으면 RETURNING 은 항상 한 행
+	doNothing := p.onConflict != "" && parser.IndexKeyword(p.onConflict, "NOTHING") != -1
+	parsedQuery.InsertOne = len(vals) == 1 && stmt.Ignore == "" && doNothing == false
+
+	// returning
+	return p.parseReturning(tbl, parsedQuery)
+}
+
+// parseOnConflict 는 Parse 에서 분리한 ON CONFLICT 절을 파싱해 placeholder 를 INSERT 의 placeholder(offset 개) 뒤 위치의 인자로 추출
+// ON CONFLICT [(cols) [WHERE ...]] DO NOTHING | DO UPDATE SET ... [WHERE ...]
+func (p *Parser) parseOnConflict(tbl *schema.Table, offset int) (placeholder int, err error) {
+	clause := p.onConflict[parser.IndexKeyword(p.onConflict, "CONFLICT")+len("CONFLICT"):]
+	doIdx := parser.IndexKeyword(clause, "DO")
+	if doIdx == -1 {
+		return 0, fmt.Errorf("parser error | on conflict needs do nothing or do update | %s", p.onConflict)
+	}
+	target, action := strings.TrimSpace(clause[:doIdx]), strings.TrimSpace(clause[doIdx+len("DO"):])
+
+	// conflict target (cols) [WHERE ...]
+	if target != "" {
+		closing := strings.IndexByte(target, ')')
+		if target[0] != '(' || closing == -1 {
+			return 0, fmt.Errorf("parser error | invalid conflict target %s", target)
+		}
+		stmtNode, err := sqlparser.Parse(fmt.Sprintf("SELECT %s FROM `%s` %s", target[1:closing], tbl.Name, target[closing+1:]))
+		if err != nil {
+			return 0, fmt.Errorf("parser error | invalid conflict target %s | %v", target, err)
+		}
+		stmt, ok := stmtNode.(*sqlparser.Select)
+		if ok != true {
+			return 0, fmt.Errorf("parser error | invalid conflict target %s", target)
+		}
+		for _, selectExpr := range stmt.SelectExprs {
+			aliased, _ := selectExpr.(*sqlparser.AliasedExpr)
+			if aliased == nil {
+				return 0, fmt.Errorf("parser error | invalid conflict target %s", target)
+			}
+			colName, _ := aliased.Expr.(*sqlparser.ColName)
+			if colName == nil {
+				continue // expression index
+			}
+			if _, ok := tbl.Column(colName.Name.String()); ok != true {
+				return 0, fmt.Errorf("parser error | not found conflict column %s in table %s", colName.Name.String(), tbl.Name)
+			}
+		}
+		if stmt.Where != nil {
+			p.shiftArgs(offset+placeholder, func() {
+				p.parseCondition(stmt.Where.Expr, "conflict_", tbl)
+			})
+		}
+		placeholder += len(FindValArgs(stmt))
+	}
+
+	// do nothing | do update set ...
+	switch {
+	case strings.EqualFold(action, "NOTHING"):
+	case len(action) > len("UPDATE") && strings.EqualFold(action[:len("UPDATE")], "UPDATE"):
+		stmtNode, err := sqlparser.Parse(fmt.Sprintf("UPDATE `%s` %s", tbl.Name, action[len("UPDATE"):]))
+		if err != nil {
+			return 0, fmt.Errorf("parser error | invalid on conflict update %s | %v", action, err)
+		}
+		stmt, ok := stmtNode.(*sqlparser.Update)
+		if ok != true {
+			return 0, fmt.Errorf("parser error | invalid on conflict update %s", action)
+		}
+		p.shiftArgs(offset+placeholder, func() {
+			for _, updateExpr := range stmt.Exprs {
+				colName := updateExpr.Name.Name.String()
+				if _, ok := tbl.Column(colName); ok != true {
+					p.unknownColumn(tbl, colName)
+				}
+				for _, valArg := range FindValArgs(updateExpr.Expr) {
+					if col, _ := tbl.Column(colName); col != nil {
+						p.bindArg(valArg, "dup_"+col.Name, p.ConvType(col.Type))
+					} else {
+						p.bindArg(valArg, "dup_"+colName, "any")
+					}
+				}
+			}
+			if stmt.Where != nil {
+				p.parseCondition(stmt.Where.Expr, "dup_where_", tbl)
+			}
+		})
+		placeholder += len(FindValArgs(stmt))
+	default:
+		return 0, fmt.Errorf("parser error | on conflict needs do nothing or do update | %s", p.onConflict)
+	}
+	return placeholder, nil
 }
 
 func (p *Parser) parseUpdate(stmt *sqlparser.Update, parsedQuery *parser.ParsedQuery) error {
@@ -149,20 +341,18 @@ func (p *Parser) parseUpdate(stmt *sqlparser.Update, parsedQuery *parser.ParsedQ
 		return err
 	}
 
-	// set
+	// set (e.g. name = ?, age = age + ?)
 	for _, updateExpr := range stmt.Exprs {
-		switch data := updateExpr.Expr.(type) {
-		case *sqlparser.SQLVal:
-			if data.Type == sqlparser.ValArg {
-				colName := updateExpr.Name.Name.String()
-				if col, _ := tbl.Column(colName); col != nil {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("set_"+col.Name, p.ConvType(col.Type)))
-				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("set_"+col.Name, "any"))
-				}
+		colName := updateExpr.Name.Name.String()
+		if _, ok := tbl.Column(colName); ok != true {
+			p.unknownColumn(tbl, colName)
+		}
+		for _, valArg := range FindValArgs(updateExpr.Expr) {
+			if col, _ := tbl.Column(colName); col != nil {
+				p.bindArg(valArg, "set_"+col.Name, p.ConvType(col.Type))
+			} else {
+				p.bindArg(valArg, "set_"+colName, "any")
 			}
-		default:
-			panic("need more programming")
 		}
 	}
 
@@ -171,7 +361,15 @@ func (p *Parser) parseUpdate(stmt *sqlparser.Update, parsedQuery *parser.ParsedQ
 	if err != nil {
 		return err
 	}
-	return nil
+
+	// order by / limit
+	for _, order := range stmt.OrderBy {
+		p.parseCondition(order.Expr, "order_", tbl)
+	}
+	p.parseLimit(stmt.Limit)
+
+	// returning
+	return p.parseReturning(tbl, parsedQuery)
 }
 
 func (p *Parser) parseDelete(stmt *sqlparser.Delete, parsedQuery *parser.ParsedQuery) error {
@@ -188,9 +386,63 @@ func (p *Parser) parseDelete(stmt *sqlparser.Delete, parsedQuery *parser.ParsedQ
 	if err != nil {
 		return err
 	}
+
+	// order by / limit
+	for _, order := range stmt.OrderBy {
+		p.parseCondition(order.Expr, "order_", tbl)
+	}
+	p.parseLimit(stmt.Limit)
+
+	// returning
+	return p.parseReturning(tbl, parsedQuery)
+}
+
+// parseSelectExprs 는 SELECT 절 (또는 RETURNING 절) 의 컬럼을 반환 필드로 추출
+func (p *Parser) parseSelectExprs(exprs sqlparser.SelectExprs, tbl *schema.Table, parsedQuery *parser.ParsedQuery) error {
+	for _, selectExpr := range exprs {
+		switch data := selectExpr.(type) {
+		case *sqlparser.StarExpr:
+			for _, col := range tbl.Columns {
+				parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(col.Name, p.ConvType(col.Type)))
+			}
+		case *sqlparser.AliasedExpr:
+			p.checkColumns(data.Expr, tbl)
+			name, goType, ok := p.resolveOperand(data.Expr, tbl)
+			if data.As.IsEmpty() != true {
+				name = data.As.String()
+			} else if ok != true {
+				return fmt.Errorf("parser error | select expression %s needs an alias", sqlparser.String(data.Expr))
+			}
+			if ok != true {
+				goType = "any"
+			}
+			parsedQuery.Ret = append(parsedQuery.Ret, parser.NewField(name, goType))
+		default:
+			return fmt.Errorf("parser error | not support select expression %s", sqlparser.String(data))
+		}
+	}
 	return nil
 }
 
+// parseReturning 은 Parse 에서 분리한 RETURNING 절을 SELECT 절로 파싱해 반환 필드로 추출
+func (p *Parser) parseReturning(tbl *schema.Table, parsedQuery *parser.ParsedQuery) error {
+	if p.returning == "" {
+		return nil
+	}
+	stmtNode, err := sqlparser.Parse(fmt.Sprintf("SELECT %s FROM `%s`", p.returning, tbl.Name))
+	if err != nil {
+		return fmt.Errorf("parser error | invalid returning clause %s | %v", p.returning, err)
+	}
+	stmt, ok := stmtNode.(*sqlparser.Select)
+	if ok != true || stmt.Limit != nil || stmt.Where != nil {
+		return fmt.Errorf("parser error | invalid returning clause %s", p.returning)
+	}
+	if len(FindValArgs(stmt.SelectExprs)) > 0 {
+		return fmt.Errorf("parser error | placeholder in returning clause is not supported")
+	}
+	return p.parseSelectExprs(stmt.SelectExprs, tbl, parsedQuery)
+}
+
 func (p *Parser) parseFrom(tableExprs sqlparser.TableExprs) (tbl *schema.Table, err error) {
 	if len(tableExprs) != 1 {
 		// TODO: select join
@@ -204,41 +456,127 @@ func (p *Parser) parseFrom(tableExprs sqlparser.TableExprs) (tbl *schema.Table,
 		panic("need more programming")
 	}
 	if tbl, _ = p.sch.Table(tableName); tbl == nil {
-		return nil, fmt.Errorf("table not found | %s", tableName)
+		return nil, parser.UnknownTableError(p.sch.Schema, tableName)
 	}
 	return tbl, nil
 }
 
+// unknownColumn 은 strict 모드에서 스키마에 없는 컬럼 참조를 에러로 기록, 아니면 any 타입으로 진행
+func (p *Parser) unknownColumn(tbl *schema.Table, name string) {
+	if p.sch.Strict == true && p.strictErr == nil {
+		p.strictErr = parser.UnknownColumnError(tbl, name)
+	}
+}
+
 func (p *Parser) parseWhere(where *sqlparser.Where, tbl *schema.Table, parsedQuery *parser.ParsedQuery) error {
-	whereFields := ParseWhereToFields(where.Expr)
-	for _, where := range whereFields {
+	if where == nil {
+		return nil
+	}
+	p.parseCondition(where.Expr, "where_", tbl)
+	return nil
+}
+
+// checkColumns 는 식 안의 모든 컬럼 참조를 스키마에서 확인, 비교 대상이 placeholder 가 아니어도 strict 모드에서 에러
+func (p *Parser) checkColumns(expr sqlparser.Expr, tbl *schema.Table) {
+	for _, column := range FindColumns(expr) {
+		if _, ok := tbl.Column(column.Name.String()); ok != true {
+			p.unknownColumn(tbl, column.Name.String())
+		}
+	}
+}
+
+// parseCondition 은 조건식의 placeholder 를 비교 대상 컬럼(또는 집계 함수) 이름에 prefix 를 붙여 인자로 추출
+func (p *Parser) parseCondition(expr sqlparser.Expr, prefix string, tbl *schema.Table) {
+	p.checkColumns(expr, tbl)
+	for _, where := range ParseWhereToFields(expr) {
 		if where.right == nil || where.left == nil {
 			continue
 		}
 		// left 의 column 을 인자로 추출
-		if paramMarkerExpr, _ := where.right.(*sqlparser.SQLVal); paramMarkerExpr != nil && paramMarkerExpr.Type == sqlparser.ValArg {
-			if data, ok := where.left.(*sqlparser.ColName); ok == true {
-				colName := data.Name.String()
-				col, ok := tbl.Column(colName)
-				if ok != true {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, "any"))
-				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, p.ConvType(col.Type)))
-				}
+		if valArg := ParseValArg(where.right); valArg != nil {
+			if name, goType, ok := p.resolveOperand(where.left, tbl); ok == true {
+				p.bindArg(valArg, prefix+name+where.suffix, goType)
 			}
 		}
 		// right 의 column 을 인자로 추출
-		if paramMarkerExpr, _ := where.left.(*sqlparser.SQLVal); paramMarkerExpr != nil && paramMarkerExpr.Type == sqlparser.ValArg {
-			if data, ok := where.right.(*sqlparser.ColName); ok == true {
-				colName := data.Name.String()
-				col, ok := tbl.Column(colName)
-				if ok != true {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, "any"))
-				} else {
-					parsedQuery.Arg = append(parsedQuery.Arg, parser.NewField("where_"+colName, p.ConvType(col.Type)))
+		if valArg := ParseValArg(where.left); valArg != nil {
+			if name, goType, ok := p.resolveOperand(where.right, tbl); ok == true {
+				p.bindArg(valArg, prefix+name+where.suffix, goType)
+			}
+		}
+		// col IN (?, ?, ...)
+		if tuple, ok := where.right.(sqlparser.ValTuple); ok == true {
+			if name, goType, ok := p.resolveOperand(where.left, tbl); ok == true {
+				for i, item := range tuple {
+					if valArg := ParseValArg(item); valArg != nil {
+						p.bindArg(valArg, fmt.Sprintf("%s%s_in_%d", prefix, name, i), goType)
+					}
 				}
 			}
 		}
 	}
-	return nil
+}
+
+// resolveOperand 는 placeholder 와 비교되는 컬럼 또는 집계 함수의 이름과 타입을 찾음 (e.g. HAVING count(*) > ?)
+func (p *Parser) resolveOperand(expr sqlparser.Expr, tbl *schema.Table) (name, goType string, ok bool) {
+	switch data := expr.(type) {
+	case *sqlparser.ColName:
+		colName := data.Name.String()
+		col, ok := tbl.Column(colName)
+		if ok != true {
+			p.unknownColumn(tbl, colName)
+			return colName, "any", true
+		}
+		return colName, p.ConvType(col.Type), true
+	case *sqlparser.FuncExpr:
+		name = strings.ToLower(data.Name.String())
+		switch name {
+		case "count":
+			return name, "int64", true
+		case "sum", "avg", "total":
+			return name, "float64", true
+		case "min", "max":
+			if len(data.Exprs) == 1 {
+				if aliased, _ := data.Exprs[0].(*sqlparser.AliasedExpr); aliased != nil {
+					if _, goType, ok := p.resolveOperand(aliased.Expr, tbl); ok == true {
+						return name, goType, true
+					}
+				}
+			}
+		}
+		return name, "any", true
+	default:
+		return "", "", false
+	}
+}
+
+// parseLimit 는 LIMIT/OFFSET 의 placeholder 를 int64 인자로 추출
+func (p *Parser) parseLimit(limit *sqlparser.Limit) {
+	if limit == nil {
+		return
+	}
+	if valArg := ParseValArg(limit.Rowcount); valArg != nil {
+		p.bindArg(valArg, "limit", "int64")
+	}
+	if valArg := ParseValArg(limit.Offset); valArg != nil {
+		p.bindArg(valArg, "offset", "int64")
+	}
+}
+
+// shiftArgs 는 따로 파싱한 절에서 연결된 인자(1..n)를 원래 쿼리의 offset 이후 위치로 옮김
+func (p *Parser) shiftArgs(offset int, bind func()) {
+	args := p.args
+	p.args = make(map[int]*parser.ParsedQueryField)
+	bind()
+	for idx, arg := range p.args {
+		args[idx+offset] = arg
+	}
+	p.args = args
+}
+
+// bindArg 는 placeholder 위치에 인자를 연결
+func (p *Parser) bindArg(valArg *sqlparser.SQLVal, name, goType string) {
+	if idx := ValArgIndex(valArg); p.args[idx] == nil {
+		p.args[idx] = parser.NewField(name, goType)
+	}
 }
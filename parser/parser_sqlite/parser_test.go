@@ -2,9 +2,13 @@ package parser_sqlite
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	"ariga.io/atlas/sql/schema"
 	"github.com/CovenantSQL/sqlparser"
+	"github.com/gosuda/ornn/config"
+	"github.com/gosuda/ornn/parser"
 	"github.com/stretchr/testify/require"
 )
 
@@ -47,3 +51,212 @@ func TestParseSqliteInsert(t *testing.T) {
 	fmt.Println(insertStmt.OnDup)
 
 }
+
+func newTestParser(t *testing.T) parser.Parser {
+	t.Helper()
+
+	users := &schema.Table{Name: "users"}
+	users.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: "integer"}}},
+		{Name: "name", Type: &schema.ColumnType{Raw: "text", Type: &schema.StringType{T: "text"}}},
+		{Name: "age", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: "integer"}}},
+	}
+
+	s := &config.Schema{}
+	s.Schema = &schema.Schema{}
+	s.AddTables(users)
+	return New(s)
+}
+
+func argNames(pq *parser.ParsedQuery) []string {
+	out := make([]string, len(pq.Arg))
+	for i, f := range pq.Arg {
+		out[i] = f.Name
+	}
+	return out
+}
+
+func TestParseLimitOffset(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("SELECT * FROM users WHERE age > ? ORDER BY id LIMIT ? OFFSET ?")
+	require.NoError(t, err)
+	require.Equal(t, []string{"where_age", "limit", "offset"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[1].GoType)
+
+	pq, err = p.Parse("SELECT * FROM users LIMIT ?, ?")
+	require.NoError(t, err)
+	require.Equal(t, []string{"offset", "limit"}, argNames(pq))
+}
+
+func TestParseHaving(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("SELECT age FROM users WHERE id IN (?, ?) AND age BETWEEN ? AND ? GROUP BY age HAVING count(*) > ?")
+	require.NoError(t, err)
+	require.Equal(t, []string{"where_id_in_0", "where_id_in_1", "where_age_from", "where_age_to", "having_count"}, argNames(pq))
+	require.Equal(t, "int64", pq.Arg[4].GoType)
+}
+
+func TestParseUnassignedPlaceholder(t *testing.T) {
+	p := newTestParser(t)
+
+	_, err := p.Parse("SELECT * FROM users WHERE id = ? OR length(?) > 5")
+	require.Error(t, err)
+}
+
+func TestParseReturning(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("INSERT INTO users (name) VALUES (?) RETURNING id, name AS user_name")
+	require.NoError(t, err)
+	require.Equal(t, "INSERT INTO users (name) VALUES (?) RETURNING id, name AS user_name", pq.Query)
+	require.Equal(t, []string{"val_name"}, argNames(pq))
+	require.Len(t, pq.Ret, 2)
+	require.Equal(t, "id", pq.Ret[0].Name)
+	require.Equal(t, "user_name", pq.Ret[1].Name)
+
+	pq, err = p.Parse("UPDATE users SET name = ? WHERE id = ? RETURNING *")
+	require.NoError(t, err)
+	require.Equal(t, []string{"set_name", "where_id"}, argNames(pq))
+	require.Len(t, pq.Ret, 3)
+
+	_, err = p.Parse("DELETE FROM users WHERE id = ? RETURNING ?")
+	require.Error(t, err)
+}
+
+func TestParseOnConflict(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse("INSERT INTO users (id, name) VALUES (?, ?) ON CONFLICT (id) WHERE age > ? DO UPDATE SET name = ?, age = age + ? WHERE age < ? RETURNING id")
+	require.NoError(t, err)
+	require.Equal(t, 6, pq.Placeholder)
+	require.Equal(t, []string{"val_id", "val_name", "conflict_age", "dup_name", "dup_age", "dup_where_age"}, argNames(pq))
+	require.Len(t, pq.Ret, 1)
+	require.True(t, pq.InsertOne)
+
+	pq, err = p.Parse("INSERT INTO users (id, name) VALUES (?, ?) ON CONFLICT DO NOTHING")
+	require.NoError(t, err)
+	require.Equal(t, []string{"val_id", "val_name"}, argNames(pq))
+	require.False(t, pq.InsertOne)
+
+	_, err = p.Parse("INSERT INTO users (id) VALUES (?) ON CONFLICT (email) DO NOTHING")
+	require.Error(t, err)
+}
+
+func TestParseUnion(t *testing.T) {
+	p := newTestParser(t)
+
+	pq, err := p.Parse(`SELECT id, name FROM users WHERE age > ? UNION SELECT id, name FROM users WHERE name = ? LIMIT ?`)
+	require.NoError(t, err)
+	require.Equal(t, parser.QueryTypeSelect, pq.QueryType)
+	require.Len(t, pq.Ret, 2)
+	require.Equal(t, []string{"where_age", "where_name", "limit"}, argNames(pq))
+
+	_, err = p.Parse(`SELECT id FROM users UNION SELECT id, name FROM users`)
+	require.Error(t, err)
+}
+
+func TestParseStrict(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	p.sch.Strict = true
+
+	_, err := p.Parse(`SELECT id FROM users WHERE agee > ?`)
+	require.EqualError(t, err, "parser error | unknown column agee in table users, did you mean age?")
+	_, err = p.Parse(`INSERT INTO users (id, nme) VALUES (?, ?)`)
+	require.ErrorContains(t, err, "did you mean name?")
+
+	// placeholder 가 아닌 비교 대상, ORDER BY, 선택 목록의 컬럼도 확인
+	_, err = p.Parse(`SELECT id FROM users WHERE nmae = 'bob'`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT id FROM users ORDER BY nmae`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT lower(nmae) AS lower_name FROM users`)
+	require.EqualError(t, err, "parser error | unknown column nmae in table users, did you mean name?")
+	_, err = p.Parse(`SELECT id, name AS n FROM users WHERE id = ? ORDER BY n`)
+	require.NoError(t, err)
+}
+
+func TestParseSyntaxErrorOffset(t *testing.T) {
+	p := newTestParser(t)
+
+	sql := "SELECT id\nFROM users\nWHERE id = ? AND name = = ?"
+	_, err := p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "="), parser.ErrorOffset(sql, err))
+
+	// named args 치환 전 sql 기준 offset
+	sql = "SELECT id FROM users WHERE name = :name_filter ORDER BY BY id"
+	_, err = p.Parse(sql)
+	require.Error(t, err)
+	require.Equal(t, strings.LastIndex(sql, "BY"), parser.ErrorOffset(sql, err))
+}
+
+func TestParseNullable(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	events := &schema.Table{Name: "events"}
+	events.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "integer", Type: &schema.IntegerType{T: "integer"}}},
+		{Name: "title", Type: &schema.ColumnType{Raw: "text", Type: &schema.StringType{T: "text"}, Null: true}},
+		{Name: "starts_at", Type: &schema.ColumnType{Raw: "datetime", Type: &schema.TimeType{T: "datetime"}, Null: true}},
+		{Name: "payload", Type: &schema.ColumnType{Raw: "blob", Type: &schema.BinaryType{T: "blob"}, Null: true}},
+	}
+	p.sch.AddTables(events)
+
+	for nullable, want := range map[string][]string{
+		config.NullablePointer: {"int64", "*string", "*TextTime", "[]byte"},
+		config.NullableSQL:     {"int64", "sql.NullString", "sql.Null[TextTime]", "[]byte"},
+		config.NullableGeneric: {"int64", "Null[string]", "Null[TextTime]", "[]byte"},
+	} {
+		p.sch.Nullable = nullable
+		pq, err := p.Parse(`SELECT id, title, starts_at, payload FROM events WHERE title = ?`)
+		require.NoError(t, err)
+		for i, field := range pq.Ret {
+			require.Equal(t, want[i], field.GoType, "%s %s", nullable, field.Name)
+		}
+		require.Equal(t, want[1], pq.Arg[0].GoType)
+	}
+}
+
+func TestConvTypeAffinity(t *testing.T) {
+	p := newTestParser(t).(*Parser)
+	for raw, want := range map[string]string{
+		"INTEGER":                "int64",
+		"SMALLINT":               "int64",
+		"BIGINT UNSIGNED":        "uint64",
+		"UNSIGNED BIG INT":       "int64",
+		"INT8":                   "int64",
+		"VARCHAR(255)":           "string",
+		"VARYING CHARACTER(255)": "string",
+		"NATIVE CHARACTER(70)":   "string",
+		"CLOB":                   "string",
+		"BLOB":                   "[]byte",
+		"DOUBLE PRECISION":       "float64",
+		"FLOAT":                  "float64",
+		"DECIMAL(10,5)":          "float64",
+		"BOOLEAN":                "bool",
+		"JSON":                   "json.RawMessage",
+		"DATETIME":               "TextTime",
+		"TIME":                   "string",
+		"":                       "any",
+		"UUID":                   "any",
+	} {
+		require.Equal(t, want, p.ConvType(&schema.ColumnType{Raw: raw}), raw)
+	}
+
+	// INTEGER PRIMARY KEY 는 rowid 라 null 로 선언되어도 값이 있음
+	logs := &schema.Table{Name: "logs"}
+	logs.Columns = []*schema.Column{
+		{Name: "id", Type: &schema.ColumnType{Raw: "INTEGER", Null: true}},
+		{Name: "seq", Type: &schema.ColumnType{Raw: "INTEGER", Null: true}},
+	}
+	logs.PrimaryKey = &schema.Index{Parts: []*schema.IndexPart{{C: logs.Columns[0]}}}
+	p.sch.AddTables(logs)
+	require.Equal(t, "int64", p.ConvType(logs.Columns[0].Type))
+	require.Equal(t, "*int64", p.ConvType(logs.Columns[1].Type))
+
+	p.sch.TimeFormat = config.TimeFormatUnix
+	require.Equal(t, "UnixTime", p.ConvType(&schema.ColumnType{Raw: "timestamp"}))
+	p.sch.TimeFormat = config.TimeFormatJulian
+	require.Equal(t, "*JulianTime", p.ConvType(&schema.ColumnType{Raw: "date", Null: true}))
+}
@@ -1,13 +1,19 @@
 package parser_sqlite
 
 import (
+	"regexp"
+	"strconv"
+	"strings"
+
 	"github.com/CovenantSQL/sqlparser"
+	"github.com/gosuda/ornn/db"
 )
 
 type binaryExpr struct {
-	left  sqlparser.Expr
-	right sqlparser.Expr
-	op    string
+	left   sqlparser.Expr
+	right  sqlparser.Expr
+	op     string
+	suffix string // arg name suffix, e.g. _from / _to for BETWEEN
 }
 
 func ParseWhereToFields(whereExpr sqlparser.Expr) []*binaryExpr {
@@ -34,6 +40,15 @@ func ParseWhereToFields(whereExpr sqlparser.Expr) []*binaryExpr {
 			left:  data.Left,
 			right: data.Right,
 		})
+	case *sqlparser.RangeCond:
+		fields = append(fields,
+			&binaryExpr{left: data.Left, right: data.From, op: ">=", suffix: "_from"},
+			&binaryExpr{left: data.Left, right: data.To, op: "<=", suffix: "_to"},
+		)
+	case *sqlparser.CaseExpr:
+		for _, when := range data.Whens {
+			fields = append(fields, ParseWhereToFields(when.Cond)...)
+		}
 	case *sqlparser.ParenExpr:
 		fields = append(fields, ParseWhereToFields(data.Expr)...)
 	case *sqlparser.NotExpr:
@@ -64,3 +79,70 @@ func ParseDriverValue(node sqlparser.Expr) (*sqlparser.ColName, *sqlparser.SQLVa
 		return nil, nil, false
 	}
 }
+
+// ParseValArg returns the node as a placeholder (?), or nil.
+func ParseValArg(node sqlparser.Expr) *sqlparser.SQLVal {
+	if data, ok := node.(*sqlparser.SQLVal); ok && data.Type == sqlparser.ValArg {
+		return data
+	}
+	return nil
+}
+
+// ValArgIndex returns the 1-based position the tokenizer gave a placeholder (":v1", ":v2", ...).
+func ValArgIndex(valArg *sqlparser.SQLVal) int {
+	idx, _ := strconv.Atoi(strings.TrimPrefix(string(valArg.Val), ":v"))
+	return idx
+}
+
+// FindValArgs collects every placeholder under the given nodes.
+func FindValArgs(nodes ...sqlparser.SQLNode) []*sqlparser.SQLVal {
+	valArgs := make([]*sqlparser.SQLVal, 0, 10)
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if expr, ok := node.(sqlparser.Expr); ok {
+			if valArg := ParseValArg(expr); valArg != nil {
+				valArgs = append(valArgs, valArg)
+			}
+		}
+		// sqlparser 는 Union 의 ORDER BY / LIMIT 를 순회하지 않음
+		if union, ok := node.(*sqlparser.Union); ok {
+			valArgs = append(valArgs, FindValArgs(union.OrderBy, union.Limit)...)
+		}
+		return true, nil
+	}, nodes...)
+	return valArgs
+}
+
+// FindColumns collects every column reference under the given nodes, not descending into subqueries.
+func FindColumns(nodes ...sqlparser.SQLNode) []*sqlparser.ColName {
+	columns := make([]*sqlparser.ColName, 0, 10)
+	sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		switch data := node.(type) {
+		case *sqlparser.Subquery:
+			return false, nil
+		case *sqlparser.ColName:
+			columns = append(columns, data)
+		}
+		return true, nil
+	}, nodes...)
+	return columns
+}
+
+var syntaxErrorPos = regexp.MustCompile(`at position (\d+)`)
+
+// syntaxErrorOffset 는 sqlparser 에러의 "at position N" 에서 에러 토큰의 시작 offset 을 찾음
+// N 은 토큰 다음 문자까지 읽은 위치라 공백과 토큰 길이만큼 되돌아감
+func syntaxErrorOffset(sql string, err error) int {
+	m := syntaxErrorPos.FindStringSubmatch(err.Error())
+	if m == nil || len(sql) == 0 {
+		return -1
+	}
+	pos, _ := strconv.Atoi(m[1])
+	offset := min(max(pos-2, 0), len(sql)-1)
+	for offset > 0 && strings.IndexByte(" \t\r\n", sql[offset]) != -1 {
+		offset--
+	}
+	for offset > 0 && db.IsIdentChar(sql[offset-1]) && db.IsIdentChar(sql[offset]) {
+		offset--
+	}
+	return offset
+}
@@ -1,51 +1,93 @@
 package parser_sqlite
 
 import (
+	"strings"
+
 	"ariga.io/atlas/sql/schema"
+	"github.com/gosuda/ornn/config"
 	"github.com/gosuda/ornn/parser"
 )
 
 func (t *Parser) ConvType(colType *schema.ColumnType) (genType string) {
+	genType = t.convType(colType)
+	// null 은 config 의 nullable 표현으로 감쌈, rowid 는 null 로 선언되어도 항상 값이 있음
+	if colType.Null && t.isRowID(colType) == false {
+		genType = parser.NullableType(genType, t.sch.Nullable)
+	}
+	return genType
+}
+
+func (t *Parser) convType(colType *schema.ColumnType) (genType string) {
+	// config 의 type_overrides 가 우선
+	if goType, ok := t.sch.OverrideType(colType); ok {
+		return goType
+	}
 	parseType := parser.ParseType(colType.Raw)
 	switch parseType.Type {
 	case "bool", "boolean":
 		genType = "bool"
-		if parseType.Nullable {
-			genType = "sql.NullBool"
-		}
-	case "int", "integer", "tinyint", "smallint", "mediumint":
-		genType = "int32"
-		if parseType.Nullable {
-			genType = "sql.NullInt32"
-		} else if parseType.Unsigned {
-			genType = "uint32"
-		}
-	case "bigint":
+	case "int", "integer", "tinyint", "smallint", "mediumint", "bigint":
+		// INTEGER affinity 는 크기와 상관없이 64bit 로 저장
 		genType = "int64"
-		if parseType.Nullable {
-			genType = "sql.NullInt64"
-		} else if parseType.Unsigned {
+		if parseType.Unsigned {
 			genType = "uint64"
 		}
-	case "numeric", "real", "double", "float", "decimal":
-		genType = "float64"
-		if parseType.Nullable {
-			genType = "sql.NullFloat64"
-		}
-	case "blob":
-		genType = "[]byte"
-	case "timestamp", "datetime", "date", "timestamp with timezone", "time with timezone", "time without timezone", "timestamp without timezone":
-		genType = "Time"
-		if parseType.Nullable {
-			genType = "*Time"
-		}
-	case "varchar", "character", "varying character", "nchar", "native character", "nvarchar", "text", "clob", "time":
+	case "json":
+		genType = "json.RawMessage"
+	case "timestamp", "datetime", "date", "timestamp with timezone", "timestamp without timezone":
+		genType = t.timeType()
+	case "time", "time with timezone", "time without timezone":
+		// 날짜가 없는 시각 (HH:MM:SS) 은 시간 형식으로 읽을 수 없어 문자열 그대로
 		genType = "string"
-		if parseType.Nullable {
-			genType = "sql.NullString"
-		}
 	default:
-		genType = "any"
+		genType = affinityType(parseType.Type)
 	}
 	return genType
 }
+
+// affinityType 는 sqlite 의 type affinity 규칙으로 선언된 타입의 go 타입을 결정
+// https://www.sqlite.org/datatype3.html#determination_of_column_affinity
+func affinityType(typ string) string {
+	switch {
+	case strings.Contains(typ, "int"):
+		return "int64"
+	case strings.Contains(typ, "char"), strings.Contains(typ, "clob"), strings.Contains(typ, "text"):
+		return "string"
+	case strings.Contains(typ, "blob"):
+		return "[]byte"
+	case strings.Contains(typ, "real"), strings.Contains(typ, "floa"), strings.Contains(typ, "doub"):
+		return "float64"
+	case typ == "numeric", typ == "decimal":
+		return "float64"
+	}
+	// 타입이 없으면 BLOB, 그 외는 NUMERIC affinity 로 어떤 값이든 저장 가능
+	return "any"
+}
+
+// isRowID 는 컬럼이 rowid 의 별칭인 INTEGER PRIMARY KEY 인지 확인
+// https://www.sqlite.org/lang_createtable.html#rowid
+func (t *Parser) isRowID(colType *schema.ColumnType) bool {
+	if t.sch == nil || t.sch.Schema == nil {
+		return false
+	}
+	for _, tbl := range t.sch.Tables {
+		if tbl.PrimaryKey == nil || len(tbl.PrimaryKey.Parts) != 1 || tbl.PrimaryKey.Parts[0].C == nil {
+			continue
+		}
+		if col := tbl.PrimaryKey.Parts[0].C; col.Type == colType {
+			return strings.EqualFold(strings.TrimSpace(colType.Raw), "integer")
+		}
+	}
+	return false
+}
+
+// timeType 는 config 의 sqlite_time_format 으로 저장되는 시간의 go 타입
+func (t *Parser) timeType() string {
+	switch t.sch.TimeFormat {
+	case config.TimeFormatUnix:
+		return "UnixTime"
+	case config.TimeFormatJulian:
+		return "JulianTime"
+	}
+	return "TextTime"
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/gosuda/ornn/db"
+)
+
+// IndexKeyword returns the index of the first keyword at the top level of sql (outside quotes,
+// comments and parentheses), or -1. Words of a keyword may be separated by any whitespace,
+// e.g. "ON CONFLICT".
+func IndexKeyword(sql, keyword string) int {
+	words := strings.Fields(keyword)
+	depth := 0
+	for i := 0; i < len(sql); i++ {
+		if end := db.SkipQuoted(sql, i); end > i {
+			i = end - 1
+			continue
+		}
+		switch sql[i] {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth == 0 && (i == 0 || !db.IsIdentChar(sql[i-1])) && matchWords(sql[i:], words) {
+			return i
+		}
+	}
+	return -1
+}
+
+func matchWords(sql string, words []string) bool {
+	for n, word := range words {
+		if n > 0 {
+			trimmed := strings.TrimLeft(sql, " \t\r\n")
+			if len(trimmed) == len(sql) {
+				return false
+			}
+			sql = trimmed
+		}
+		if len(sql) < len(word) || !strings.EqualFold(sql[:len(word)], word) {
+			return false
+		}
+		sql = sql[len(word):]
+		if sql != "" && db.IsIdentChar(sql[0]) {
+			return false
+		}
+	}
+	return true
+}
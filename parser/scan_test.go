@@ -0,0 +1,17 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexKeyword(t *testing.T) {
+	sql := "INSERT INTO t (a) VALUES ('returning') RETURNING id"
+	require.Equal(t, 39, IndexKeyword(sql, "RETURNING"))
+	require.Equal(t, -1, IndexKeyword("SELECT returning_id FROM t", "RETURNING"))
+	require.Equal(t, -1, IndexKeyword("SELECT * FROM t WHERE id IN (SELECT id FROM u UNION SELECT 1)", "UNION"))
+
+	sql = "INSERT INTO t VALUES (?) ON\n  conflict (id) DO NOTHING"
+	require.Equal(t, 25, IndexKeyword(sql, "ON CONFLICT"))
+}
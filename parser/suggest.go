@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"ariga.io/atlas/sql/schema"
+)
+
+// UnknownColumnError reports a column missing from the table, suggesting the closest column.
+func UnknownColumnError(tbl *schema.Table, name string) error {
+	candidates := make([]string, len(tbl.Columns))
+	for i, col := range tbl.Columns {
+		candidates[i] = col.Name
+	}
+	scope := "table " + tbl.Name
+	if strings.HasPrefix(tbl.Name, "__") { // virtual table of joined tables
+		scope = "joined tables"
+	}
+	return unknownError(fmt.Sprintf("column %s in %s", name, scope), name, candidates)
+}
+
+// UnknownTableError reports a table missing from the schema, suggesting the closest table.
+func UnknownTableError(sch *schema.Schema, name string) error {
+	var candidates []string
+	if sch != nil {
+		for _, tbl := range sch.Tables {
+			candidates = append(candidates, tbl.Name)
+		}
+	}
+	return unknownError("table "+name, name, candidates)
+}
+
+func unknownError(what, name string, candidates []string) error {
+	msg := "parser error | unknown " + what
+	if suggestion := Suggest(name, candidates); suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %s?", suggestion)
+	}
+	return &Error{
+		Msg:    msg,
+		Offset: -1,
+		Ident:  name,
+	}
+}
+
+// Suggest returns the candidate closest to name by edit distance, or "" if none is close enough.
+func Suggest(name string, candidates []string) (suggestion string) {
+	best := max(2, len(name)/3) + 1
+	for _, cand := range candidates {
+		if d := EditDistance(strings.ToLower(name), strings.ToLower(cand)); d < best {
+			suggestion, best = cand, d
+		}
+	}
+	return suggestion
+}
+
+// EditDistance returns the levenshtein distance between a and b.
+func EditDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,24 @@
+package parser
+
+import (
+	"testing"
+
+	"ariga.io/atlas/sql/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEditDistance(t *testing.T) {
+	require.Equal(t, 0, EditDistance("name", "name"))
+	require.Equal(t, 2, EditDistance("nmae", "name"))
+	require.Equal(t, 3, EditDistance("kitten", "sitting"))
+	require.Equal(t, 4, EditDistance("", "user"))
+}
+
+func TestUnknownError(t *testing.T) {
+	tbl := schema.NewTable("users").AddColumns(schema.NewColumn("id"), schema.NewColumn("email"))
+	require.EqualError(t, UnknownColumnError(tbl, "emial"), "parser error | unknown column emial in table users, did you mean email?")
+	require.EqualError(t, UnknownColumnError(tbl, "created_at"), "parser error | unknown column created_at in table users")
+
+	sch := schema.New("public").AddTables(tbl, schema.NewTable("orders"))
+	require.EqualError(t, UnknownTableError(sch, "user"), "parser error | unknown table user, did you mean users?")
+}
@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/gosuda/ornn/config"
 )
 
 // Type holds information for a database type.
@@ -22,7 +25,7 @@ func ParseType(typ string) *Type {
 		typ, isArray = typ[:len(typ)-len("[]")], true
 	}
 	unsigned := false
-	if strings.HasSuffix(typ, " unsigned") {
+	if len(typ) > len(" unsigned") && strings.EqualFold(typ[len(typ)-len(" unsigned"):], " unsigned") {
 		typ, unsigned = typ[:len(typ)-len(" unsigned")], true
 	}
 	var prec, scale int
@@ -43,3 +46,138 @@ func ParseType(typ string) *Type {
 		Unsigned: unsigned,
 	}
 }
+
+// SetOperationType returns the go type of a UNION / INTERSECT / EXCEPT result column from the types of two branches.
+// Integers widen to the larger one, integers and floats to float64, and a nullable branch makes the result nullable.
+func SetOperationType(left, right string) (goType string, ok bool) {
+	switch {
+	case left == right:
+		return left, true
+	case left == "any":
+		return right, true
+	case right == "any":
+		return left, true
+	}
+
+	leftBase, leftNull := nullableBase(left)
+	rightBase, rightNull := nullableBase(right)
+	switch {
+	case leftBase == rightBase:
+		goType = leftBase
+	case numericRank[leftBase] > 0 && numericRank[rightBase] > 0:
+		goType = leftBase
+		if numericRank[rightBase] > numericRank[leftBase] {
+			goType = rightBase
+		}
+		if strings.HasPrefix(leftBase, "float") != strings.HasPrefix(rightBase, "float") {
+			goType = "float64"
+		}
+	default:
+		return "", false
+	}
+	if leftNull == false && rightNull == false {
+		return goType, true
+	}
+
+	// nullable 한 branch 의 표현 (포인터, sql.Null*, Null[T]) 을 유지
+	for _, typ := range []string{left, right} {
+		if base, null := nullableBase(typ); null == true && base == goType {
+			return typ, true
+		}
+	}
+	nullable := config.NullablePointer
+	for _, typ := range []string{left, right} {
+		switch {
+		case strings.HasPrefix(typ, "Null["):
+			nullable = config.NullableGeneric
+		case strings.HasPrefix(typ, "sql.Null"):
+			nullable = config.NullableSQL
+		}
+	}
+	return NullableType(goType, nullable), true
+}
+
+// MergeSetOperationRet checks a set operation branch against the result columns (taken from the first branch)
+// and widens the result types to fit it.
+func MergeSetOperationRet(op string, ret, branch []*ParsedQueryField) error {
+	if len(ret) != len(branch) {
+		return fmt.Errorf("parser error | each %s query must have the same number of columns (%d, %d)", op, len(ret), len(branch))
+	}
+	for i, field := range ret {
+		goType, ok := SetOperationType(field.GoType, branch[i].GoType)
+		if ok != true {
+			return fmt.Errorf("parser error | %s column %s types %s and %s cannot be matched", op, field.Name, field.GoType, branch[i].GoType)
+		}
+		field.GoType = goType
+	}
+	return nil
+}
+
+// numeric go types by width, integers rank below floats of the same width
+var numericRank = map[string]int{
+	"int8": 1, "uint8": 1, "int16": 2, "uint16": 2, "int32": 3, "uint32": 3, "float32": 4,
+	"int64": 5, "uint64": 5, "int": 5, "uint": 5, "float64": 6,
+}
+
+// nullableBase returns the go type under a pointer, sql.Null* or Null[T] wrapper
+func nullableBase(goType string) (base string, nullable bool) {
+	switch {
+	case strings.HasPrefix(goType, "*"):
+		return goType[1:], true
+	case strings.HasPrefix(goType, "Null[") && strings.HasSuffix(goType, "]"):
+		return goType[len("Null[") : len(goType)-1], true
+	case strings.HasPrefix(goType, "sql.Null[") && strings.HasSuffix(goType, "]"):
+		return goType[len("sql.Null[") : len(goType)-1], true
+	}
+	for base, null := range sqlNullTypes {
+		if null == goType {
+			return base, true
+		}
+	}
+	return goType, false
+}
+
+// DecimalType returns the go type of a decimal / numeric column in the decimal representation, float64 if empty.
+// The string representation never scans through a float, integer columns which fit in int64 are int64.
+func DecimalType(typ *Type, decimal string) string {
+	if decimal != config.DecimalString {
+		return "float64"
+	}
+	if typ.Scale == 0 && typ.Prec > 0 && typ.Prec <= 18 {
+		return "int64"
+	}
+	return "string"
+}
+
+// sql.Null* types by the go type they hold
+var sqlNullTypes = map[string]string{
+	"string":    "sql.NullString",
+	"bool":      "sql.NullBool",
+	"uint8":     "sql.NullByte",
+	"int16":     "sql.NullInt16",
+	"int32":     "sql.NullInt32",
+	"int64":     "sql.NullInt64",
+	"float64":   "sql.NullFloat64",
+	"time.Time": "sql.NullTime",
+}
+
+// NullableType returns the go type of a nullable column holding goType in the nullable representation,
+// pointer if empty. Types which hold NULL themselves (pointers, slices, maps, any, JSON[T] ...) are kept.
+func NullableType(goType, nullable string) string {
+	switch {
+	case goType == "any", goType == "json.RawMessage", goType == "hstore.Hstore",
+		strings.HasPrefix(goType, "*"), strings.HasPrefix(goType, "[]"), strings.HasPrefix(goType, "map["),
+		strings.HasPrefix(goType, "JSON["), strings.HasPrefix(goType, "Null["), strings.HasPrefix(goType, "sql.Null"):
+		return goType
+	}
+	switch nullable {
+	case config.NullableSQL:
+		if null, ok := sqlNullTypes[goType]; ok == true {
+			return null
+		}
+		return "sql.Null[" + goType + "]"
+	case config.NullableGeneric:
+		return "Null[" + goType + "]"
+	}
+	return "*" + goType
+}
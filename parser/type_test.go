@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/gosuda/ornn/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetOperationType(t *testing.T) {
+	for _, c := range []struct {
+		left, right, want string
+		ok                bool
+	}{
+		{"int64", "int64", "int64", true},
+		{"any", "string", "string", true},
+		{"int32", "int64", "int64", true},
+		{"int32", "float32", "float64", true},
+		{"*int32", "int64", "*int64", true},
+		{"sql.NullString", "string", "sql.NullString", true},
+		{"sql.NullInt32", "int64", "sql.NullInt64", true},
+		{"Null[int32]", "int64", "Null[int64]", true},
+		{"sql.Null[uint32]", "uint32", "sql.Null[uint32]", true},
+		{"string", "int64", "", false},
+	} {
+		goType, ok := SetOperationType(c.left, c.right)
+		require.Equal(t, c.ok, ok, "%s, %s", c.left, c.right)
+		require.Equal(t, c.want, goType, "%s, %s", c.left, c.right)
+	}
+}
+
+func TestMergeSetOperationRet(t *testing.T) {
+	ret := []*ParsedQueryField{NewField("id", "int32"), NewField("name", "any")}
+	require.NoError(t, MergeSetOperationRet("UNION", ret, []*ParsedQueryField{NewField("id", "int64"), NewField("title", "string")}))
+	require.Equal(t, "int64", ret[0].GoType)
+	require.Equal(t, "string", ret[1].GoType)
+
+	require.Error(t, MergeSetOperationRet("UNION", ret, []*ParsedQueryField{NewField("id", "int64")}))
+	require.Error(t, MergeSetOperationRet("EXCEPT", ret, []*ParsedQueryField{NewField("id", "bool"), NewField("name", "string")}))
+}
+
+func TestNullableType(t *testing.T) {
+	for _, c := range []struct {
+		goType, nullable, want string
+	}{
+		{"string", "", "*string"},
+		{"string", config.NullablePointer, "*string"},
+		{"string", config.NullableSQL, "sql.NullString"},
+		{"time.Time", config.NullableSQL, "sql.NullTime"},
+		{"uint32", config.NullableSQL, "sql.Null[uint32]"},
+		{"MemberRole", config.NullableGeneric, "Null[MemberRole]"},
+		{"[]byte", config.NullableGeneric, "[]byte"},
+		{"json.RawMessage", config.NullableSQL, "json.RawMessage"},
+		{"JSON[Profile]", config.NullablePointer, "JSON[Profile]"},
+		{"any", config.NullablePointer, "any"},
+	} {
+		require.Equal(t, c.want, NullableType(c.goType, c.nullable), "%s, %s", c.goType, c.nullable)
+	}
+}
+
+func TestDecimalType(t *testing.T) {
+	for _, c := range []struct {
+		typ, decimal, want string
+	}{
+		{"decimal(20,2)", "", "float64"},
+		{"decimal(20,2)", config.DecimalFloat, "float64"},
+		{"decimal(20,2)", config.DecimalString, "string"},
+		{"numeric", config.DecimalString, "string"},
+		{"decimal(10,0)", config.DecimalString, "int64"},
+		{"numeric(18)", config.DecimalString, "int64"},
+		{"numeric(19)", config.DecimalString, "string"},
+	} {
+		require.Equal(t, c.want, DecimalType(ParseType(c.typ), c.decimal), "%s, %s", c.typ, c.decimal)
+	}
+}